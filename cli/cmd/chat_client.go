@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -15,8 +14,40 @@ import (
 
 // ChatMessage represents a single chat message
 type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []ChatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// ChatToolCall is one function call the assistant asked to make, in the
+// OpenAI tool-calling schema. Arguments is a JSON-encoded object, not a
+// parsed map, matching how the API represents it on the wire.
+type ChatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// ChatToolDef describes one tool the assistant may call, in the OpenAI
+// "function" tool schema sent on ChatRequest.Tools.
+type ChatToolDef struct {
+	Type     string          `json:"type"`
+	Function ChatToolDefFunc `json:"function"`
+}
+
+// ChatToolDefFunc is the function half of a ChatToolDef. Parameters is a
+// JSON-Schema object describing the function's arguments; its concrete Go
+// type varies by caller (chat_tools.go builds it from a typed schema loaded
+// from YAML), so it's left as any here to match the arbitrary-JSON shape
+// the API expects.
+type ChatToolDefFunc struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
 }
 
 // ChatRequest represents the request payload for the chat API
@@ -35,6 +66,7 @@ type ChatRequest struct {
 	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
 	PresencePenalty  *float64           `json:"presence_penalty,omitempty"`
 	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	Tools            []ChatToolDef      `json:"tools,omitempty"`
 }
 
 // ChatChoice represents a choice in the chat response
@@ -63,18 +95,54 @@ type ChatSessionContext struct {
 	MaxTokens   int
 	Streaming   bool
 	HTTPClient  HTTPClient
+
+	// Tools, when non-empty, is sent as the request's "tools" field so the
+	// assistant may return tool_calls instead of (or alongside) content.
+	Tools []ChatToolDef
+
+	// LastFinishReason and LastToolCalls are populated from the most recent
+	// response by sendChatRequestWithContext/sendChatRequestStreamWithContext,
+	// mirroring how SessionID is already threaded back from the server.
+	// Callers driving a tool-calling loop (see chat_tools.go) read these
+	// after each send to decide whether to execute tools and resubmit.
+	LastFinishReason string
+	LastToolCalls    []ChatToolCall
+
+	// StreamTimeout bounds how long Stream (chat_stream.go) will wait for the
+	// next SSE event before treating the connection as stalled and
+	// reconnecting it the same way as a network error. Zero disables the
+	// watchdog and waits indefinitely, matching this client's historical
+	// behavior.
+	StreamTimeout time.Duration
+
+	// OnToken, OnToolCall, OnError, and OnUsage, if set, are called for each
+	// matching ChatStreamEvent as Stream decodes it, in addition to the
+	// event being sent on Stream's returned channel. Use these when a caller
+	// wants to react to one event kind without a type switch over every
+	// event the turn produces.
+	OnToken    func(ChatStreamEvent)
+	OnToolCall func(ChatStreamEvent)
+	OnError    func(ChatStreamEvent)
+	OnUsage    func(ChatStreamEvent)
 }
 
+// chatToolDefsGlobal holds the tool definitions loaded from --tools for the
+// lifetime of the process; set once in chatCmd.RunE, read by
+// newDefaultContextFromGlobals so every REPL turn advertises them.
+var chatToolDefsGlobal []ChatToolDef
+
 func newDefaultContextFromGlobals() *ChatSessionContext {
 	return &ChatSessionContext{
-		ServerURL:   serverURL,
-		Namespace:   namespace,
-		ProjectID:   projectID,
-		SessionID:   sessionID,
-		Temperature: temperature,
-		MaxTokens:   maxTokens,
-		Streaming:   streaming,
-		HTTPClient:  getHTTPClient(),
+		ServerURL:     serverURL,
+		Namespace:     namespace,
+		ProjectID:     projectID,
+		SessionID:     sessionID,
+		Temperature:   temperature,
+		MaxTokens:     maxTokens,
+		Streaming:     streaming,
+		HTTPClient:    getHTTPClient(),
+		Tools:         chatToolDefsGlobal,
+		StreamTimeout: time.Duration(streamTimeoutSecs) * time.Second,
 	}
 }
 
@@ -94,12 +162,14 @@ func sendChatRequest(messages []ChatMessage) (*ChatResponse, error) {
 	ctx := newDefaultContextFromGlobals()
 	resp, err := sendChatRequestWithContext(messages, ctx)
 	sessionID = ctx.SessionID
+	chatLastFinishReason = ctx.LastFinishReason
+	chatLastToolCalls = ctx.LastToolCalls
 	return resp, err
 }
 
 func sendChatRequestWithContext(messages []ChatMessage, ctx *ChatSessionContext) (*ChatResponse, error) {
 	url := buildChatAPIURL(ctx)
-	request := ChatRequest{Messages: messages}
+	request := ChatRequest{Messages: messages, Tools: ctx.Tools}
 	if !strings.Contains(url, "/v1/projects/") {
 		meta := map[string]string{}
 		if ctx.Namespace != "" {
@@ -147,7 +217,7 @@ func sendChatRequestWithContext(messages []ChatMessage, ctx *ChatSessionContext)
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned error %d: %s", resp.StatusCode, prettyServerError(resp, body))
+		return nil, serverError(resp, body)
 	}
 
 	var chatResponse ChatResponse
@@ -157,21 +227,47 @@ func sendChatRequestWithContext(messages []ChatMessage, ctx *ChatSessionContext)
 	if sessionIDHeader := resp.Header.Get("X-Session-ID"); sessionIDHeader != "" {
 		ctx.SessionID = sessionIDHeader
 	}
+	if len(chatResponse.Choices) > 0 {
+		ctx.LastFinishReason = chatResponse.Choices[0].FinishReason
+		ctx.LastToolCalls = chatResponse.Choices[0].Message.ToolCalls
+	}
 	return &chatResponse, nil
 }
 
-// sendChatRequestStream connects to the server with stream=true and returns the full assistant message.
-func sendChatRequestStream(messages []ChatMessage) (string, error) {
+// chatLastFinishReason and chatLastToolCalls mirror ChatSessionContext's
+// fields of the same name for the stateless sendChatRequest/
+// sendChatRequestStream wrappers, which build a throwaway ChatSessionContext
+// per call (see sessionID above for the same pattern).
+var (
+	chatLastFinishReason string
+	chatLastToolCalls    []ChatToolCall
+)
+
+// sendChatRequestStream connects to the server with stream=true, writes each
+// content delta to out as it arrives, and returns the full assistant
+// message. reqCtx governs the HTTP request only: canceling it (e.g. from a
+// per-request Ctrl-C) stops this one response without affecting the chat
+// session. out must be the same writer the caller uses for everything else
+// on screen (e.g. a chatTerminal's term.Terminal rather than os.Stdout
+// directly): in the interactive REPL's raw mode, writing straight to
+// os.Stdout would desync the line editor's cursor tracking and skip the
+// \n -> \r\n translation term.Terminal otherwise provides.
+func sendChatRequestStream(reqCtx context.Context, out io.Writer, messages []ChatMessage) (string, error) {
 	ctx := newDefaultContextFromGlobals()
-	out, err := sendChatRequestStreamWithContext(messages, ctx)
+	result, err := sendChatRequestStreamWithContext(reqCtx, out, messages, ctx)
 	sessionID = ctx.SessionID
-	return out, err
+	chatLastFinishReason = ctx.LastFinishReason
+	chatLastToolCalls = ctx.LastToolCalls
+	return result, err
 }
 
-func sendChatRequestStreamWithContext(messages []ChatMessage, ctx *ChatSessionContext) (string, error) {
+// buildChatStreamRequest builds the HTTP request for a streaming chat
+// completion, shared by the first connection and every reconnect attempt in
+// chat_stream.go (which adds its own "Last-Event-ID" header on top of this).
+func buildChatStreamRequest(reqCtx context.Context, messages []ChatMessage, ctx *ChatSessionContext) (*http.Request, error) {
 	url := buildChatAPIURL(ctx)
 	streamTrue := true
-	request := ChatRequest{Messages: messages, Stream: &streamTrue}
+	request := ChatRequest{Messages: messages, Stream: &streamTrue, Tools: ctx.Tools}
 	if !strings.Contains(url, "/v1/projects/") {
 		meta := map[string]string{}
 		if ctx.Namespace != "" {
@@ -185,14 +281,12 @@ func sendChatRequestStreamWithContext(messages []ChatMessage, ctx *ChatSessionCo
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	reqCtx, cancel := context.WithCancel(context.Background())
-	defer cancel()
 	req, err := http.NewRequestWithContext(reqCtx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "text/event-stream")
@@ -205,72 +299,97 @@ func sendChatRequestStreamWithContext(messages []ChatMessage, ctx *ChatSessionCo
 		fmt.Fprintf(os.Stderr, "HTTP %s %s\n", req.Method, req.URL.String())
 		logHeaders("request", req.Header)
 	}
+	return req, nil
+}
+
+// sendChatRequestStreamWithContext drives ctx.Stream (chat_stream.go) and
+// assembles its events back into the plain string this function has always
+// returned, so callers that just want the final text don't need to know
+// about the channel-based API. Content deltas are written to out as they
+// arrive, exactly as before; the reconnect/backoff/heartbeat-timeout
+// machinery is entirely ctx.Stream's concern and invisible here.
+func sendChatRequestStreamWithContext(reqCtx context.Context, out io.Writer, messages []ChatMessage, ctx *ChatSessionContext) (string, error) {
+	ctx.LastFinishReason = ""
+	ctx.LastToolCalls = nil
 
-	hc := &http.Client{Timeout: 0, Transport: &http.Transport{DisableCompression: true}}
-	resp, err := hc.Do(req)
+	events, err := ctx.Stream(reqCtx, messages)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return "", fmt.Errorf("server returned error %d and body read failed: %v", resp.StatusCode, readErr)
-		}
-		return "", fmt.Errorf("server returned error %d: %s", resp.StatusCode, prettyServerError(resp, body))
-	}
-	if debug {
-		fmt.Fprintf(os.Stderr, "  -> %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
-		logHeaders("response", resp.Header)
-	}
-	if sessionIDHeader := resp.Header.Get("X-Session-ID"); sessionIDHeader != "" {
-		ctx.SessionID = sessionIDHeader
+		return "", err
 	}
 
-	reader := bufio.NewReader(resp.Body)
-	writer := bufio.NewWriter(os.Stdout)
-	defer writer.Flush()
 	var builder strings.Builder
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return "", fmt.Errorf("stream read error: %w", err)
-		}
-		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
+	toolCallsByIndex := map[int]*ChatToolCall{}
+	var toolCallOrder []int
+	var streamErr error
+
+	for ev := range events {
+		switch ev.Type {
+		case ChatStreamEventError:
+			streamErr = fmt.Errorf("stream error: %s", string(ev.Data))
 			continue
-		}
-		if !strings.HasPrefix(line, "data:") {
+		case ChatStreamEventToken, ChatStreamEventToolCall:
+			// fall through to delta parsing below
+		default:
 			continue
 		}
-		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-		if payload == "[DONE]" {
-			break
-		}
+
 		var chunk struct {
 			Choices []struct {
 				Delta struct {
-					Role    string `json:"role,omitempty"`
-					Content string `json:"content,omitempty"`
+					Role      string `json:"role,omitempty"`
+					Content   string `json:"content,omitempty"`
+					ToolCalls []struct {
+						Index    int    `json:"index"`
+						ID       string `json:"id,omitempty"`
+						Type     string `json:"type,omitempty"`
+						Function struct {
+							Name      string `json:"name,omitempty"`
+							Arguments string `json:"arguments,omitempty"`
+						} `json:"function"`
+					} `json:"tool_calls,omitempty"`
 				} `json:"delta"`
+				FinishReason string `json:"finish_reason,omitempty"`
 			} `json:"choices"`
 		}
-		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+		if err := json.Unmarshal(ev.Data, &chunk); err != nil {
 			continue
 		}
 		if len(chunk.Choices) == 0 {
 			continue
 		}
-		delta := chunk.Choices[0].Delta
-		if delta.Content != "" {
-			_, _ = writer.WriteString(delta.Content)
-			_ = writer.Flush()
-			builder.WriteString(delta.Content)
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			ctx.LastFinishReason = choice.FinishReason
+		}
+		if choice.Delta.Content != "" {
+			_, _ = fmt.Fprint(out, choice.Delta.Content)
+			builder.WriteString(choice.Delta.Content)
+		}
+		for _, tc := range choice.Delta.ToolCalls {
+			call, ok := toolCallsByIndex[tc.Index]
+			if !ok {
+				call = &ChatToolCall{}
+				toolCallsByIndex[tc.Index] = call
+				toolCallOrder = append(toolCallOrder, tc.Index)
+			}
+			if tc.ID != "" {
+				call.ID = tc.ID
+			}
+			if tc.Type != "" {
+				call.Type = tc.Type
+			}
+			if tc.Function.Name != "" {
+				call.Function.Name = tc.Function.Name
+			}
+			call.Function.Arguments += tc.Function.Arguments
 		}
 	}
+	for _, idx := range toolCallOrder {
+		ctx.LastToolCalls = append(ctx.LastToolCalls, *toolCallsByIndex[idx])
+	}
+	if streamErr != nil {
+		return builder.String(), streamErr
+	}
 	return builder.String(), nil
 }
 
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// chatScriptOptions holds the flags that put `lf projects chat` into
+// scriptable, non-interactive mode: a single turn in, one response out,
+// suitable for piping into jq or using as a curl replacement in CI.
+type chatScriptOptions struct {
+	Prompt     string
+	PromptFile string
+	Input      string // "-" means read the user turn from stdin
+	System     string
+	SystemFile string
+	Output     string // "text", "json", or "jsonl"
+	NoStream   bool
+	Raw        bool
+}
+
+// isScriptable reports whether any of the one-shot input flags were set,
+// i.e. whether chatCmd should run a single turn instead of the interactive
+// REPL.
+func (o chatScriptOptions) isScriptable() bool {
+	return o.Prompt != "" || o.PromptFile != "" || o.Input != ""
+}
+
+// chatScriptDelta is the JSON shape printed by --output json (once, for the
+// whole response) and --output jsonl (once per streamed delta).
+type chatScriptDelta struct {
+	Role         string          `json:"role,omitempty"`
+	Delta        string          `json:"delta,omitempty"`
+	FinishReason string          `json:"finish_reason,omitempty"`
+	Usage        json.RawMessage `json:"usage,omitempty"`
+}
+
+func printScriptJSON(d chatScriptDelta) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// resolveScriptMessage returns the single user turn to send, from whichever
+// of --prompt, --prompt-file, or --input - was set.
+func resolveScriptMessage(o chatScriptOptions) (string, error) {
+	switch {
+	case o.Prompt != "":
+		return o.Prompt, nil
+	case o.PromptFile != "":
+		data, err := os.ReadFile(o.PromptFile)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", o.PromptFile, err)
+		}
+		return strings.TrimRight(string(data), "\n"), nil
+	case o.Input == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading stdin: %w", err)
+		}
+		return strings.TrimRight(string(data), "\n"), nil
+	default:
+		return "", fmt.Errorf("one of --prompt, --prompt-file, or --input - is required")
+	}
+}
+
+// resolveScriptSystemMessage returns the optional system message to prepend
+// to conversationHistory, from --system or --system-file.
+func resolveScriptSystemMessage(o chatScriptOptions) (string, error) {
+	switch {
+	case o.System != "":
+		return o.System, nil
+	case o.SystemFile != "":
+		data, err := os.ReadFile(o.SystemFile)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", o.SystemFile, err)
+		}
+		return strings.TrimRight(string(data), "\n"), nil
+	default:
+		return "", nil
+	}
+}
+
+// runScriptableChat drives a single chat turn non-interactively for
+// `lf projects chat --prompt/--prompt-file/--input -`, printing the
+// response per --output/--raw. It returns the process exit code: non-zero
+// on a server/network error or a non-"stop" finish reason, so callers can
+// detect truncation the same way they would from a raw curl response.
+func runScriptableChat(o chatScriptOptions) int {
+	switch o.Output {
+	case "", "text", "json", "jsonl":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --output %q (want text, json, or jsonl)\n", o.Output)
+		return 1
+	}
+
+	message, err := resolveScriptMessage(o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	system, err := resolveScriptSystemMessage(o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var history []ChatMessage
+	if system != "" {
+		history = append(history, ChatMessage{Role: "system", Content: system})
+	}
+	history = append(history, ChatMessage{Role: "user", Content: message})
+
+	sessCtx := newDefaultContextFromGlobals()
+
+	var finishReason string
+	if o.NoStream {
+		finishReason, err = runScriptChatOnce(sessCtx, history, o)
+	} else {
+		finishReason, err = runScriptChatStream(sessCtx, history, o)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if finishReason != "" && finishReason != "stop" {
+		fmt.Fprintf(os.Stderr, "Warning: response did not finish normally (finish_reason=%s)\n", finishReason)
+		return 1
+	}
+	return 0
+}
+
+// runScriptChatOnce sends history with stream=false and prints the single
+// response per o.Output/o.Raw. It returns the response's finish_reason.
+func runScriptChatOnce(sessCtx *ChatSessionContext, history []ChatMessage, o chatScriptOptions) (string, error) {
+	url := buildChatAPIURL(sessCtx)
+	request := ChatRequest{Messages: history}
+	if !strings.Contains(url, "/v1/projects/") {
+		request.Metadata = scriptRequestMetadata(sessCtx)
+	}
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server returned error %d: %s", resp.StatusCode, prettyServerError(resp, body))
+	}
+
+	var chatResponse ChatResponse
+	if err := json.Unmarshal(body, &chatResponse); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(chatResponse.Choices) == 0 {
+		return "", fmt.Errorf("server returned no choices")
+	}
+	choice := chatResponse.Choices[0]
+
+	var raw struct {
+		Usage json.RawMessage `json:"usage,omitempty"`
+	}
+	_ = json.Unmarshal(body, &raw)
+
+	switch {
+	case o.Raw:
+		os.Stdout.Write(body)
+		fmt.Println()
+	case o.Output == "json" || o.Output == "jsonl":
+		printScriptJSON(chatScriptDelta{Role: choice.Message.Role, Delta: choice.Message.Content, FinishReason: choice.FinishReason, Usage: raw.Usage})
+	default:
+		fmt.Println(choice.Message.Content)
+	}
+	return choice.FinishReason, nil
+}
+
+// runScriptChatStream sends history with stream=true and relays the SSE
+// response per o.Output/o.Raw: text prints deltas as they arrive, jsonl
+// prints one chatScriptDelta per delta, json accumulates silently and
+// prints one chatScriptDelta at the end, and raw relays each `data:`
+// payload verbatim. It returns the stream's last finish_reason.
+func runScriptChatStream(sessCtx *ChatSessionContext, history []ChatMessage, o chatScriptOptions) (string, error) {
+	url := buildChatAPIURL(sessCtx)
+	streamTrue := true
+	request := ChatRequest{Messages: history, Stream: &streamTrue}
+	if !strings.Contains(url, "/v1/projects/") {
+		request.Metadata = scriptRequestMetadata(sessCtx)
+	}
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	hc := &http.Client{Timeout: 0, Transport: &http.Transport{DisableCompression: true}}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("server returned error %d: %s", resp.StatusCode, prettyServerError(resp, body))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var builder strings.Builder
+	var lastUsage json.RawMessage
+	finishReason := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("stream read error: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			break
+		}
+		if o.Raw {
+			fmt.Println(payload)
+			continue
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Role    string `json:"role,omitempty"`
+					Content string `json:"content,omitempty"`
+				} `json:"delta"`
+				FinishReason string `json:"finish_reason,omitempty"`
+			} `json:"choices"`
+			Usage json.RawMessage `json:"usage,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue
+		}
+		if len(chunk.Usage) > 0 {
+			lastUsage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			finishReason = choice.FinishReason
+		}
+		if choice.Delta.Content != "" {
+			builder.WriteString(choice.Delta.Content)
+		}
+		switch o.Output {
+		case "jsonl":
+			printScriptJSON(chatScriptDelta{Role: choice.Delta.Role, Delta: choice.Delta.Content, FinishReason: choice.FinishReason, Usage: chunk.Usage})
+		case "json":
+			// Accumulated above; printed once the stream ends.
+		default:
+			if choice.Delta.Content != "" {
+				fmt.Print(choice.Delta.Content)
+			}
+		}
+	}
+
+	switch o.Output {
+	case "json":
+		printScriptJSON(chatScriptDelta{Delta: builder.String(), FinishReason: finishReason, Usage: lastUsage})
+	default:
+		if !o.Raw && o.Output != "jsonl" {
+			fmt.Println()
+		}
+	}
+	return finishReason, nil
+}
+
+// scriptRequestMetadata mirrors the namespace/project_id metadata the
+// interactive REPL attaches when talking to the non-project-scoped
+// inference endpoint (see sendChatRequestWithContext).
+func scriptRequestMetadata(sessCtx *ChatSessionContext) map[string]string {
+	meta := map[string]string{}
+	if sessCtx.Namespace != "" {
+		meta["namespace"] = sessCtx.Namespace
+	}
+	if sessCtx.ProjectID != "" {
+		meta["project_id"] = sessCtx.ProjectID
+	}
+	return meta
+}
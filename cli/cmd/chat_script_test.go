@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveScriptMessage(t *testing.T) {
+	got, err := resolveScriptMessage(chatScriptOptions{Prompt: "hi there"})
+	if err != nil || got != "hi there" {
+		t.Fatalf("expected %q, got %q (err=%v)", "hi there", got, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	if err := os.WriteFile(path, []byte("from a file\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	got, err = resolveScriptMessage(chatScriptOptions{PromptFile: path})
+	if err != nil || got != "from a file" {
+		t.Fatalf("expected %q, got %q (err=%v)", "from a file", got, err)
+	}
+
+	if _, err := resolveScriptMessage(chatScriptOptions{}); err == nil {
+		t.Fatalf("expected an error when none of --prompt/--prompt-file/--input is set")
+	}
+}
+
+func TestRunScriptChatOnce_JSONOutput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"total_tokens":3}}`)
+	}))
+	defer ts.Close()
+
+	sessCtx := &ChatSessionContext{ServerURL: ts.URL}
+	finishReason, err := runScriptChatOnce(sessCtx, []ChatMessage{{Role: "user", Content: "hi"}}, chatScriptOptions{Output: "json"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if finishReason != "stop" {
+		t.Fatalf("expected finish_reason %q, got %q", "stop", finishReason)
+	}
+}
+
+func TestRunScriptChatStream_FinishReasonTruncated(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"choices":[{"delta":{"content":"partial"}}]}`+"\n\n")
+		io.WriteString(w, `data: {"choices":[{"delta":{},"finish_reason":"length"}]}`+"\n\n")
+		io.WriteString(w, `data: [DONE]`+"\n\n")
+	}))
+	defer ts.Close()
+
+	sessCtx := &ChatSessionContext{ServerURL: ts.URL}
+	finishReason, err := runScriptChatStream(sessCtx, []ChatMessage{{Role: "user", Content: "hi"}}, chatScriptOptions{Output: "text"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if finishReason != "length" {
+		t.Fatalf("expected finish_reason %q, got %q", "length", finishReason)
+	}
+}
+
+func TestRunScriptableChat_RejectsUnknownOutput(t *testing.T) {
+	code := runScriptableChat(chatScriptOptions{Prompt: "hi", Output: "xml"})
+	if code == 0 {
+		t.Fatalf("expected a non-zero exit code for an invalid --output value")
+	}
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"llamafarm-cli/internal/errs"
+)
+
+// ChatStreamEventType names the kind of event carried by a ChatStreamEvent,
+// taken from the SSE "event:" field. Servers that omit "event:" (the
+// OpenAI-style delta chunks this client has always spoken) are treated as
+// ChatStreamEventToken, since a content/tool-call delta is what that shape
+// always carries.
+type ChatStreamEventType string
+
+const (
+	ChatStreamEventToken    ChatStreamEventType = "token"
+	ChatStreamEventToolCall ChatStreamEventType = "tool_call"
+	ChatStreamEventError    ChatStreamEventType = "error"
+	ChatStreamEventUsage    ChatStreamEventType = "usage"
+)
+
+// ChatStreamEvent is one decoded SSE event from a chat completion stream.
+// Data is the raw "data:" payload (possibly multi-line, joined with "\n" per
+// the SSE spec); callers that know the event's shape unmarshal it themselves.
+type ChatStreamEvent struct {
+	Type ChatStreamEventType
+	ID   string
+	Data []byte
+}
+
+// Default and maximum backoff between reconnect attempts when a stream
+// disconnects mid-turn without a server-provided "retry:" override.
+const (
+	chatStreamInitialBackoff    = 500 * time.Millisecond
+	chatStreamMaxBackoff        = 10 * time.Second
+	chatStreamMaxReconnectTries = 5
+)
+
+// Stream opens a streaming chat completion and returns a channel of decoded
+// SSE events. The channel is closed when the turn completes (a "[DONE]"
+// payload or a clean EOF with no reconnect left to attempt), when reqCtx is
+// canceled, or when a non-retryable error occurs; in every case the final
+// send on the channel, if any, is followed by the channel closing, so
+// `for ev := range events` drains cleanly without a separate error return.
+//
+// On a mid-turn disconnect (a network error, or EOF before "[DONE]"), Stream
+// reconnects automatically using the Last-Event-ID of the most recent event
+// that carried an "id:" field, backing off exponentially between attempts
+// (honoring the server's most recent "retry:" field, in milliseconds, as the
+// base delay). ctx.StreamTimeout, if set, bounds how long Stream will wait
+// for the *next* event before treating the connection as stalled and
+// retrying it the same way as a network error.
+//
+// Named SSE events ("event: token|tool_call|error|usage") are dispatched to
+// ctx's OnToken/OnToolCall/OnError/OnUsage handlers, if set, in addition to
+// being sent on the returned channel; unnamed events (no "event:" field) are
+// treated as ChatStreamEventToken to match the OpenAI-style delta chunks
+// this client already speaks.
+func (ctx *ChatSessionContext) Stream(reqCtx context.Context, messages []ChatMessage) (<-chan ChatStreamEvent, error) {
+	resp, err := openChatStream(reqCtx, messages, ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan ChatStreamEvent)
+	go driveChatStream(reqCtx, resp, messages, ctx, events)
+	return events, nil
+}
+
+// openChatStream sends the chat completion request with stream=true,
+// resuming from lastEventID (via the "Last-Event-ID" header) if non-empty,
+// and returns the response if it's a reconnectable event-stream. Callers own
+// resp.Body and must close it.
+func openChatStream(reqCtx context.Context, messages []ChatMessage, ctx *ChatSessionContext, lastEventID string) (*http.Response, error) {
+	req, err := buildChatStreamRequest(reqCtx, messages, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	hc := &http.Client{Timeout: 0, Transport: &http.Transport{DisableCompression: true}}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, errs.New(errs.ErrServer, fmt.Errorf("server returned error %d and body read failed: %w", resp.StatusCode, readErr))
+		}
+		return nil, serverError(resp, body)
+	}
+	if sessionIDHeader := resp.Header.Get("X-Session-ID"); sessionIDHeader != "" {
+		ctx.SessionID = sessionIDHeader
+	}
+	return resp, nil
+}
+
+// driveChatStream owns resp and pumps events onto the channel until the turn
+// completes, reconnecting on mid-turn disconnects, then closes the channel.
+func driveChatStream(reqCtx context.Context, resp *http.Response, messages []ChatMessage, ctx *ChatSessionContext, events chan<- ChatStreamEvent) {
+	defer close(events)
+
+	var lastEventID string
+	backoff := chatStreamInitialBackoff
+	var serverRetry time.Duration
+	for attempt := 0; ; {
+		done, retryMS, err := pumpChatStream(reqCtx, resp, ctx, events, &lastEventID)
+		resp.Body.Close()
+		if done {
+			return
+		}
+		if reqCtx.Err() != nil {
+			return
+		}
+
+		attempt++
+		if attempt > chatStreamMaxReconnectTries {
+			emitChatStreamError(reqCtx, events, fmt.Errorf("stream disconnected after %d reconnect attempts: %w", attempt-1, err))
+			return
+		}
+
+		if retryMS > 0 {
+			serverRetry = time.Duration(retryMS) * time.Millisecond
+		}
+		wait := backoff
+		if serverRetry > 0 {
+			// The server's retry: value is the base delay, doubled per attempt.
+			wait = serverRetry << (attempt - 1)
+		}
+		logger.Debug("chat stream disconnected, reconnecting", "attempt", attempt, "wait", wait, "err", err)
+		select {
+		case <-time.After(wait):
+		case <-reqCtx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > chatStreamMaxBackoff {
+			backoff = chatStreamMaxBackoff
+		}
+
+		next, openErr := openChatStream(reqCtx, messages, ctx, lastEventID)
+		if openErr != nil {
+			emitChatStreamError(reqCtx, events, openErr)
+			return
+		}
+		resp = next
+	}
+}
+
+// pumpChatStream reads SSE frames from resp.Body until the turn completes
+// ("[DONE]"), the connection is lost, or no frame arrives within
+// ctx.StreamTimeout. done is true only when the turn completed normally;
+// otherwise err explains why the caller should reconnect, and retryMS, if
+// non-zero, is the most recently seen server "retry:" value.
+func pumpChatStream(reqCtx context.Context, resp *http.Response, ctx *ChatSessionContext, events chan<- ChatStreamEvent, lastEventID *string) (done bool, retryMS int, err error) {
+	reader := bufio.NewReader(resp.Body)
+	watcher := newChatStreamWatchdog(resp.Body, ctx.StreamTimeout)
+	defer watcher.stop()
+
+	for {
+		frame, ok, readErr := readSSEFrame(reader)
+		watcher.kick()
+		if !ok {
+			if readErr == nil || readErr == io.EOF {
+				return false, retryMS, io.EOF
+			}
+			return false, retryMS, readErr
+		}
+
+		if frame.id != "" {
+			*lastEventID = frame.id
+		}
+		if frame.retry > 0 {
+			retryMS = frame.retry
+		}
+		data := strings.TrimSpace(frame.data)
+		if data == "[DONE]" {
+			return true, 0, nil
+		}
+		if data == "" && frame.event == "" {
+			// Comment-only or field-less frame (e.g. a bare keepalive); nothing to emit.
+			continue
+		}
+
+		emitChatStreamEvent(reqCtx, ctx, events, frame)
+
+		if reqCtx.Err() != nil {
+			return false, retryMS, reqCtx.Err()
+		}
+		if readErr != nil {
+			// The frame completed exactly at EOF, with no trailing blank line.
+			return false, retryMS, io.EOF
+		}
+	}
+}
+
+func emitChatStreamEvent(reqCtx context.Context, ctx *ChatSessionContext, events chan<- ChatStreamEvent, frame sseFrame) {
+	evType := ChatStreamEventType(strings.TrimSpace(frame.event))
+	if evType == "" {
+		evType = ChatStreamEventToken
+	}
+	ev := ChatStreamEvent{Type: evType, ID: frame.id, Data: []byte(frame.data)}
+
+	switch evType {
+	case ChatStreamEventToken:
+		if ctx.OnToken != nil {
+			ctx.OnToken(ev)
+		}
+	case ChatStreamEventToolCall:
+		if ctx.OnToolCall != nil {
+			ctx.OnToolCall(ev)
+		}
+	case ChatStreamEventError:
+		if ctx.OnError != nil {
+			ctx.OnError(ev)
+		}
+	case ChatStreamEventUsage:
+		if ctx.OnUsage != nil {
+			ctx.OnUsage(ev)
+		}
+	}
+
+	select {
+	case events <- ev:
+	case <-reqCtx.Done():
+	}
+}
+
+func emitChatStreamError(reqCtx context.Context, events chan<- ChatStreamEvent, err error) {
+	ev := ChatStreamEvent{Type: ChatStreamEventError, Data: []byte(err.Error())}
+	select {
+	case events <- ev:
+	case <-reqCtx.Done():
+	}
+}
+
+// chatStreamWatchdog closes a stream's body if no frame arrives within the
+// given timeout, turning a stalled connection into an ordinary read error
+// that pumpChatStream/driveChatStream already know how to reconnect from. A
+// nil *chatStreamWatchdog (timeout <= 0) disables this behavior entirely.
+type chatStreamWatchdog struct {
+	timer *time.Timer
+	d     time.Duration
+}
+
+func newChatStreamWatchdog(closer io.Closer, d time.Duration) *chatStreamWatchdog {
+	if d <= 0 {
+		return nil
+	}
+	return &chatStreamWatchdog{d: d, timer: time.AfterFunc(d, func() { closer.Close() })}
+}
+
+// kick resets the watchdog's deadline; call it after every frame read.
+func (w *chatStreamWatchdog) kick() {
+	if w == nil {
+		return
+	}
+	w.timer.Reset(w.d)
+}
+
+func (w *chatStreamWatchdog) stop() {
+	if w == nil {
+		return
+	}
+	w.timer.Stop()
+}
+
+// sseFrame is one parsed SSE record: the fields accumulated between blank
+// lines, per the EventSource spec (https://html.spec.whatwg.org/#server-sent-events).
+type sseFrame struct {
+	id    string
+	event string
+	data  string
+	retry int
+}
+
+// readSSEFrame reads lines from r until a blank line terminates the current
+// frame, and returns it. Comment lines (leading ":") are ignored per spec.
+// ok is false only when no frame data was accumulated before the read error
+// (typically io.EOF on a clean connection close between turns); if a partial
+// frame was read right up to the error (no trailing blank line), it's
+// returned with ok=true alongside the error so callers can still act on it.
+func readSSEFrame(r *bufio.Reader) (frame sseFrame, ok bool, err error) {
+	var dataLines []string
+	for {
+		line, readErr := r.ReadString('\n')
+		hasContent := len(line) > 0
+		line = strings.TrimRight(line, "\r\n")
+
+		if hasContent {
+			ok = true
+			if line == "" {
+				frame.data = strings.Join(dataLines, "\n")
+				return frame, true, nil
+			}
+			if !strings.HasPrefix(line, ":") {
+				field, value := splitSSEField(line)
+				switch field {
+				case "event":
+					frame.event = value
+				case "data":
+					dataLines = append(dataLines, value)
+				case "id":
+					frame.id = value
+				case "retry":
+					if n, convErr := strconv.Atoi(value); convErr == nil {
+						frame.retry = n
+					}
+				}
+			}
+		}
+
+		if readErr != nil {
+			frame.data = strings.Join(dataLines, "\n")
+			return frame, ok, readErr
+		}
+	}
+}
+
+// splitSSEField splits an SSE line into its field name and value, per spec:
+// everything up to the first colon is the field name, a single leading
+// space on the value (if present) is stripped, and a line with no colon is
+// a field with an empty value.
+func splitSSEField(line string) (field, value string) {
+	idx := strings.IndexByte(line, ':')
+	if idx < 0 {
+		return line, ""
+	}
+	field = line[:idx]
+	value = strings.TrimPrefix(line[idx+1:], " ")
+	return field, value
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// ANSI escapes for role prefixes and command output. These are written
+// unconditionally (no isTTY gate, matching how the rest of chatTerminal
+// assumes an interactive stdin/stdout) since raw mode is only entered when
+// stdin is already a terminal.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiCyan   = "\x1b[36m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// maxChatHistoryLines bounds the persisted history file so it doesn't grow
+// without limit across the lifetime of a machine.
+const maxChatHistoryLines = 1000
+
+// chatHistoryPath returns $XDG_DATA_HOME/llamafarm/history, falling back to
+// ~/.local/share/llamafarm/history per the XDG base directory spec when
+// XDG_DATA_HOME isn't set.
+func chatHistoryPath() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "llamafarm", "history"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "llamafarm", "history"), nil
+}
+
+// loadChatHistory reads the persisted history file, oldest line first. A
+// missing file is not an error: it just means no history yet.
+func loadChatHistory() []string {
+	path, err := chatHistoryPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// appendChatHistory appends line to the persisted history file, creating it
+// (and its parent directory) if necessary, and drops the oldest lines once
+// the file holds more than maxChatHistoryLines. Failures are silently
+// ignored: history is a convenience, not something worth interrupting a
+// chat session over.
+func appendChatHistory(line string) {
+	path, err := chatHistoryPath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	lines := append(loadChatHistory(), line)
+	if len(lines) > maxChatHistoryLines {
+		lines = lines[len(lines)-maxChatHistoryLines:]
+	}
+	_ = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
+
+// stdio adapts the separate os.Stdin/os.Stdout streams to the single
+// io.ReadWriter that term.NewTerminal expects.
+type stdio struct {
+	io.Reader
+	io.Writer
+}
+
+// chatTerminal is a raw-mode line editor for the chat REPL, built on
+// golang.org/x/term.Terminal. It layers two things term.Terminal doesn't
+// provide natively: history that survives across sessions (term.Terminal's
+// recall ring buffer is in-memory only, with no public seeding API), and a
+// minimal Ctrl-R reverse search (term.Terminal has no reverse-search key
+// binding at all). Both are implemented via AutoCompleteCallback, which
+// term.Terminal invokes on every keypress before applying its own editing.
+type chatTerminal struct {
+	term     *term.Terminal
+	fd       int
+	oldState *term.State
+
+	history []string // persisted lines, oldest first; grows as lines are submitted
+
+	searching   bool
+	searchQuery string
+	searchAt    int // next index (exclusive) to search backward from
+}
+
+// newChatTerminal puts stdin into raw mode and returns a chatTerminal ready
+// to read lines. Callers must call Close to restore the terminal, typically
+// via defer right after a successful call.
+func newChatTerminal() (*chatTerminal, error) {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("putting terminal into raw mode: %w", err)
+	}
+	t := &chatTerminal{
+		term:     term.NewTerminal(stdio{os.Stdin, os.Stdout}, ""),
+		fd:       fd,
+		oldState: oldState,
+		history:  loadChatHistory(),
+	}
+	t.term.AutoCompleteCallback = t.autoComplete
+	if w, h, err := term.GetSize(fd); err == nil {
+		_ = t.term.SetSize(w, h)
+	}
+	return t, nil
+}
+
+// Close restores the terminal to its state from before newChatTerminal.
+func (t *chatTerminal) Close() {
+	_ = term.Restore(t.fd, t.oldState)
+}
+
+// SetSize forwards a window size change (SIGWINCH) to the underlying
+// terminal so line wrapping stays correct.
+func (t *chatTerminal) SetSize(width, height int) {
+	_ = t.term.SetSize(width, height)
+}
+
+// Printf writes to the terminal through term.Terminal.Write, which is
+// required for anything printed outside ReadLine: writing straight to
+// os.Stdout would desync the editor's idea of what's on screen. The
+// (int, error) return matches fmt.Printf's so it can stand in for it
+// wherever a print func is threaded through (see printChatBanner).
+func (t *chatTerminal) Printf(format string, args ...any) (int, error) {
+	return fmt.Fprintf(t.term, format, args...)
+}
+
+// ReadLine prompts with prompt and reads one line, resetting any in-progress
+// Ctrl-R search left over from a previous call.
+func (t *chatTerminal) ReadLine(prompt string) (string, error) {
+	t.searching = false
+	t.term.SetPrompt(prompt)
+	return t.term.ReadLine()
+}
+
+// Remember appends line to both the in-memory and on-disk history so it's
+// available to Ctrl-R search for the rest of this process and future ones.
+func (t *chatTerminal) Remember(line string) {
+	t.history = append(t.history, line)
+	appendChatHistory(line)
+}
+
+// ctrlR is the ASCII control code for Ctrl-R (DC2), sent by terminals on
+// that keypress.
+const ctrlR = 18
+
+// autoComplete implements Ctrl-R reverse search. On the first Ctrl-R it
+// captures the current line as the search query and returns the most recent
+// history entry containing it; each subsequent Ctrl-R (before any other key)
+// continues the search further back. Any other key ends the search and is
+// processed normally.
+func (t *chatTerminal) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != ctrlR {
+		t.searching = false
+		return "", 0, false
+	}
+	if !t.searching {
+		t.searching = true
+		t.searchQuery = line
+		t.searchAt = len(t.history)
+	}
+	for i := t.searchAt - 1; i >= 0; i-- {
+		if t.searchQuery == "" || strings.Contains(t.history[i], t.searchQuery) {
+			t.searchAt = i
+			return t.history[i], len(t.history[i]), true
+		}
+	}
+	// No earlier match: leave the line as it is.
+	return line, pos, true
+}
+
+// watchResize forwards SIGWINCH to t.SetSize for the lifetime of the
+// returned stop function's caller; call stop when the chat session ends.
+func watchResize(t *chatTerminal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if w, h, err := term.GetSize(t.fd); err == nil {
+					t.SetSize(w, h)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// readMultilineBody reads lines from t until one that is exactly "." and
+// joins the rest with newlines, implementing the /multiline command's
+// heredoc-style input.
+func readMultilineBody(t *chatTerminal) (string, error) {
+	var lines []string
+	for {
+		line, err := t.ReadLine("... ")
+		if err != nil {
+			return "", err
+		}
+		if line == "." {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
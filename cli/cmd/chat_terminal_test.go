@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChatHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+
+	if got := loadChatHistory(); got != nil {
+		t.Fatalf("expected no history before anything is written, got %v", got)
+	}
+
+	appendChatHistory("first message")
+	appendChatHistory("second message")
+
+	got := loadChatHistory()
+	want := []string{"first message", "second message"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	path, err := chatHistoryPath()
+	if err != nil {
+		t.Fatalf("chatHistoryPath: %v", err)
+	}
+	if filepath.Dir(path) != filepath.Join(dir, "llamafarm") {
+		t.Fatalf("expected history under %s/llamafarm, got %s", dir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected history file to exist: %v", err)
+	}
+}
+
+func TestChatTerminalAutoComplete_CtrlRSearch(t *testing.T) {
+	ct := &chatTerminal{history: []string{"list datasets", "help me debug auth", "list projects"}}
+
+	// First Ctrl-R with an empty line starts from the most recent entry.
+	line, pos, ok := ct.autoComplete("", 0, ctrlR)
+	if !ok || line != "list projects" {
+		t.Fatalf("expected most recent history entry, got %q (ok=%v)", line, ok)
+	}
+	if pos != len("list projects") {
+		t.Fatalf("expected cursor at end of match, got %d", pos)
+	}
+
+	// A second Ctrl-R continues searching backward for the same query.
+	line, _, ok = ct.autoComplete(line, pos, ctrlR)
+	if !ok || line != "help me debug auth" {
+		t.Fatalf("expected to continue back to the earlier match, got %q (ok=%v)", line, ok)
+	}
+
+	// Any other key ends the search.
+	_, _, ok = ct.autoComplete(line, len(line), 'x')
+	if ok {
+		t.Fatalf("expected a non-Ctrl-R key to end the search")
+	}
+	if ct.searching {
+		t.Fatalf("expected searching to be reset after a non-Ctrl-R key")
+	}
+}
+
+func TestChatTerminalAutoComplete_QueryFiltersMatches(t *testing.T) {
+	ct := &chatTerminal{history: []string{"list datasets", "help me debug auth", "list projects"}}
+
+	line, _, ok := ct.autoComplete("auth", 4, ctrlR)
+	if !ok || line != "help me debug auth" {
+		t.Fatalf("expected the only entry containing %q, got %q (ok=%v)", "auth", line, ok)
+	}
+
+	// No earlier match for the same query: the line is left as it is.
+	line, _, ok = ct.autoComplete(line, len(line), ctrlR)
+	if !ok || line != "help me debug auth" {
+		t.Fatalf("expected search to stay on the only match, got %q (ok=%v)", line, ok)
+	}
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// chatMaxToolIterations bounds the tool-calling loop so a misbehaving
+// assistant that keeps requesting tool calls can't hang a chat turn
+// forever.
+const chatMaxToolIterations = 8
+
+// chatToolParamSchema is a minimal JSON-Schema subset for a tool's
+// parameters, loaded from --tools YAML and re-marshaled as JSON on the
+// outgoing request (see ChatToolDefFunc.Parameters).
+type chatToolParamSchema struct {
+	Type        string                          `yaml:"type" json:"type,omitempty"`
+	Description string                          `yaml:"description,omitempty" json:"description,omitempty"`
+	Properties  map[string]*chatToolParamSchema `yaml:"properties,omitempty" json:"properties,omitempty"`
+	Items       *chatToolParamSchema            `yaml:"items,omitempty" json:"items,omitempty"`
+	Required    []string                        `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum        []string                        `yaml:"enum,omitempty" json:"enum,omitempty"`
+}
+
+// chatToolSpec is one tool entry in a --tools YAML file: the name/
+// description/parameters schema sent to the model, plus exactly one of Exec
+// or HTTP describing how the CLI carries out a call locally.
+type chatToolSpec struct {
+	Name        string               `yaml:"name"`
+	Description string               `yaml:"description"`
+	Parameters  *chatToolParamSchema `yaml:"parameters,omitempty"`
+	Exec        string               `yaml:"exec,omitempty"`
+	HTTP        string               `yaml:"http,omitempty"`
+}
+
+// chatToolFile is the top-level shape of a --tools YAML file.
+type chatToolFile struct {
+	Tools []chatToolSpec `yaml:"tools"`
+}
+
+// chatToolSpecsGlobal holds the tools loaded from --tools for the lifetime
+// of the process, looked up by name when the assistant returns tool_calls.
+var chatToolSpecsGlobal []chatToolSpec
+
+// loadChatTools parses a --tools YAML file, validating that every tool has
+// a name and exactly one of exec/http to invoke it with.
+func loadChatTools(path string) ([]chatToolSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tools file %s: %w", path, err)
+	}
+	var file chatToolFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing tools file %s: %w", path, err)
+	}
+	for i, spec := range file.Tools {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("tool #%d in %s: name is required", i, path)
+		}
+		if (spec.Exec == "") == (spec.HTTP == "") {
+			return nil, fmt.Errorf("tool %q in %s: exactly one of exec or http is required", spec.Name, path)
+		}
+	}
+	return file.Tools, nil
+}
+
+// chatToolDefs converts specs to the OpenAI tool-calling schema sent on
+// ChatRequest.Tools.
+func chatToolDefs(specs []chatToolSpec) []ChatToolDef {
+	defs := make([]ChatToolDef, 0, len(specs))
+	for _, spec := range specs {
+		defs = append(defs, ChatToolDef{
+			Type: "function",
+			Function: ChatToolDefFunc{
+				Name:        spec.Name,
+				Description: spec.Description,
+				Parameters:  spec.Parameters,
+			},
+		})
+	}
+	return defs
+}
+
+// findChatToolSpec returns the spec named name, or nil if --tools didn't
+// define one.
+func findChatToolSpec(specs []chatToolSpec, name string) *chatToolSpec {
+	for i := range specs {
+		if specs[i].Name == name {
+			return &specs[i]
+		}
+	}
+	return nil
+}
+
+// confirmChatToolCall prompts y/n on stdin before running a tool call. It's
+// the gate behind --tool-confirm that keeps a tool's exec/http call from
+// running silently off the back of whatever the model decided to do.
+func confirmChatToolCall(name, argsJSON string) bool {
+	fmt.Fprintf(os.Stderr, ansiYellow+"Run tool %s(%s)? [y/N] "+ansiReset, name, argsJSON)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.ToLower(strings.TrimSpace(line))
+	return line == "y" || line == "yes"
+}
+
+// runChatTool executes one tool call per its spec (shelling out to Exec, or
+// POSTing to HTTP) and returns the result text to send back as a "tool"
+// role message. argsJSON is the model's raw tool-call arguments object,
+// passed through verbatim as the command's stdin or the request body.
+func runChatTool(spec *chatToolSpec, argsJSON string) (string, error) {
+	switch {
+	case spec.Exec != "":
+		return runChatToolExec(spec.Exec, argsJSON)
+	case spec.HTTP != "":
+		return runChatToolHTTP(spec.HTTP, argsJSON)
+	default:
+		return "", fmt.Errorf("tool %q has neither exec nor http configured", spec.Name)
+	}
+}
+
+func runChatToolExec(command, argsJSON string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = strings.NewReader(argsJSON)
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running tool command: %w: %s", err, strings.TrimSpace(errOut.String()))
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+func runChatToolHTTP(url, argsJSON string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(argsJSON))
+	if err != nil {
+		return "", fmt.Errorf("building tool request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("calling tool endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading tool response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("tool endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// runChatTurn sends history through send, and — if the assistant comes back
+// with tool_calls — executes each one (gated by --tool-confirm) against
+// chatToolSpecsGlobal, appends the assistant and tool-result messages to
+// history, and resubmits, repeating until finish_reason stops being
+// "tool_calls" or chatMaxToolIterations is hit. It returns the final
+// assistant content.
+//
+// send is either a streaming or non-streaming single request/response
+// round trip; either way, it's expected to leave chatLastFinishReason and
+// chatLastToolCalls set to that round's result (see sendChatRequest and
+// sendChatRequestStream). print is used for the "[tool] ..." / "[tool
+// result] ..." lines, routed through whichever writer the caller already
+// uses (os.Stdout, or a chatTerminal's Printf) so raw-mode output stays
+// correct.
+func runChatTurn(history *[]ChatMessage, print func(format string, args ...any) (int, error), send func([]ChatMessage) (string, error)) (string, error) {
+	for i := 0; i < chatMaxToolIterations; i++ {
+		content, err := send(*history)
+		if err != nil {
+			return "", err
+		}
+		toolCalls := chatLastToolCalls
+		if chatLastFinishReason != "tool_calls" || len(toolCalls) == 0 {
+			*history = append(*history, ChatMessage{Role: "assistant", Content: content})
+			return content, nil
+		}
+
+		*history = append(*history, ChatMessage{Role: "assistant", Content: content, ToolCalls: toolCalls})
+		for _, call := range toolCalls {
+			print("\n" + ansiCyan + "[tool] " + call.Function.Name + "(" + call.Function.Arguments + ")" + ansiReset + "\n")
+			result := runChatToolCall(call)
+			print(ansiGreen+"[tool result]"+ansiReset+" %s\n", result)
+			*history = append(*history, ChatMessage{Role: "tool", ToolCallID: call.ID, Content: result})
+		}
+	}
+	return "", fmt.Errorf("tool-calling loop exceeded %d iterations", chatMaxToolIterations)
+}
+
+// runChatToolCall looks up call's spec, optionally confirms with the user,
+// and runs it, turning a missing spec, a rejected confirmation, or an
+// execution error into an error-string result rather than failing the
+// whole turn: the assistant gets to see and react to the failure, same as
+// any other tool result.
+func runChatToolCall(call ChatToolCall) string {
+	spec := findChatToolSpec(chatToolSpecsGlobal, call.Function.Name)
+	if spec == nil {
+		return fmt.Sprintf("error: no tool named %q is configured", call.Function.Name)
+	}
+	if chatToolConfirm && !confirmChatToolCall(call.Function.Name, call.Function.Arguments) {
+		return "error: tool call rejected by user"
+	}
+	result, err := runChatTool(spec, call.Function.Arguments)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeChatToolsFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing tools file: %v", err)
+	}
+	return path
+}
+
+func TestLoadChatTools(t *testing.T) {
+	path := writeChatToolsFile(t, `
+tools:
+  - name: echo
+    description: Echo the input back
+    parameters:
+      type: object
+      properties:
+        text:
+          type: string
+      required: [text]
+    exec: "cat"
+`)
+	specs, err := loadChatTools(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(specs) != 1 || specs[0].Name != "echo" || specs[0].Exec != "cat" {
+		t.Fatalf("unexpected specs: %+v", specs)
+	}
+
+	defs := chatToolDefs(specs)
+	if len(defs) != 1 || defs[0].Type != "function" || defs[0].Function.Name != "echo" {
+		t.Fatalf("unexpected defs: %+v", defs)
+	}
+	b, err := json.Marshal(defs[0])
+	if err != nil {
+		t.Fatalf("marshaling tool def: %v", err)
+	}
+	for _, want := range []string{`"name":"echo"`, `"type":"object"`, `"text"`} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("expected marshaled def to contain %q, got %s", want, b)
+		}
+	}
+}
+
+func TestLoadChatTools_RequiresExactlyOneOfExecOrHTTP(t *testing.T) {
+	bothPath := writeChatToolsFile(t, `
+tools:
+  - name: broken
+    exec: "cat"
+    http: "http://example.com"
+`)
+	if _, err := loadChatTools(bothPath); err == nil {
+		t.Fatalf("expected an error when both exec and http are set")
+	}
+
+	neitherPath := writeChatToolsFile(t, `
+tools:
+  - name: broken
+`)
+	if _, err := loadChatTools(neitherPath); err == nil {
+		t.Fatalf("expected an error when neither exec nor http is set")
+	}
+}
+
+func TestRunChatToolExec(t *testing.T) {
+	out, err := runChatToolExec("cat", `{"text":"hello"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != `{"text":"hello"}` {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if _, err := runChatToolExec("exit 1", ""); err == nil {
+		t.Fatalf("expected an error for a failing command")
+	}
+}
+
+func TestRunChatTurn_ExecutesToolCallsAndResubmits(t *testing.T) {
+	chatToolSpecsGlobal = []chatToolSpec{{Name: "echo", Exec: "cat"}}
+	chatToolConfirm = false
+	defer func() {
+		chatToolSpecsGlobal = nil
+	}()
+
+	calls := 0
+	send := func(h []ChatMessage) (string, error) {
+		calls++
+		if calls == 1 {
+			chatLastFinishReason = "tool_calls"
+			chatLastToolCalls = []ChatToolCall{{
+				ID:   "call_1",
+				Type: "function",
+				Function: struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				}{Name: "echo", Arguments: `{"text":"hi"}`},
+			}}
+			return "", nil
+		}
+		chatLastFinishReason = "stop"
+		chatLastToolCalls = nil
+		// By the second round trip, the tool result should already be in history.
+		last := h[len(h)-1]
+		if last.Role != "tool" || last.ToolCallID != "call_1" {
+			t.Fatalf("expected last message to be the tool result, got %+v", last)
+		}
+		return "done", nil
+	}
+
+	var history []ChatMessage
+	var printed []string
+	print := func(format string, args ...any) (int, error) {
+		printed = append(printed, fmt.Sprintf(format, args...))
+		return 0, nil
+	}
+
+	content, err := runChatTurn(&history, print, send)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "done" {
+		t.Fatalf("expected final content %q, got %q", "done", content)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 round trips, got %d", calls)
+	}
+	if len(printed) == 0 {
+		t.Fatalf("expected tool invocation/result lines to be printed")
+	}
+}
+
+func TestRunChatTurn_NoToolCalls(t *testing.T) {
+	send := func(h []ChatMessage) (string, error) {
+		chatLastFinishReason = "stop"
+		chatLastToolCalls = nil
+		return "hello", nil
+	}
+	var history []ChatMessage
+	content, err := runChatTurn(&history, fmt.Printf, send)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", content)
+	}
+	if len(history) != 1 || history[0].Role != "assistant" {
+		t.Fatalf("expected a single assistant message in history, got %+v", history)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// saveChatTranscript writes history as indented JSON to path, for the
+// /save <path> chat command.
+func saveChatTranscript(path string, history []ChatMessage) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadChatTranscript reads a conversation previously written by
+// saveChatTranscript, for the /load <path> chat command.
+func loadChatTranscript(path string) ([]ChatMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var history []ChatMessage
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return history, nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestChatTranscriptRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conversation.json")
+	history := []ChatMessage{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "hi there"},
+	}
+
+	if err := saveChatTranscript(path, history); err != nil {
+		t.Fatalf("saveChatTranscript: %v", err)
+	}
+
+	got, err := loadChatTranscript(path)
+	if err != nil {
+		t.Fatalf("loadChatTranscript: %v", err)
+	}
+	if len(got) != len(history) {
+		t.Fatalf("expected %d messages, got %d", len(history), len(got))
+	}
+	for i := range history {
+		if got[i].Role != history[i].Role || got[i].Content != history[i].Content {
+			t.Fatalf("message %d: expected %+v, got %+v", i, history[i], got[i])
+		}
+	}
+}
+
+func TestLoadChatTranscript_MissingFile(t *testing.T) {
+	if _, err := loadChatTranscript(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected an error for a missing transcript file")
+	}
+}
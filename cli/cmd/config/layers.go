@@ -0,0 +1,362 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// envConfigLayers, when set, is an explicit, ordered list of overlay files
+// to merge on top of the base config, separated by os.PathListSeparator
+// (":" on Unix). It overrides the default llamafarm.d/conf.d directory scan
+// done by resolveConfigLayers.
+const envConfigLayers = "LF_CONFIG_LAYERS"
+
+// envSelector, when set, picks an environment-specific overlay file next to
+// the base config (e.g. llamafarm.yaml + LF_ENV=dev -> llamafarm.dev.yaml),
+// applied after the conf.d layers.
+const envSelector = "LF_ENV"
+
+// overlayDirNames are the sibling directories resolveConfigLayers scans for
+// *.yaml/*.yml overlays, in priority order: the first of these that exists
+// next to the base config wins and the rest are ignored.
+var overlayDirNames = []string{"llamafarm.d", "conf.d"}
+
+// resolveConfigLayers returns the ordered list of overlay files to merge on
+// top of the base config at configPath. LF_CONFIG_LAYERS takes priority if
+// set; otherwise every *.yaml/*.yml file in the first existing llamafarm.d/
+// or conf.d/ directory next to configPath is used, lexically sorted.
+func resolveConfigLayers(configPath string) []string {
+	if raw := os.Getenv(envConfigLayers); raw != "" {
+		var layers []string
+		for _, p := range strings.Split(raw, string(os.PathListSeparator)) {
+			if p = strings.TrimSpace(p); p != "" {
+				layers = append(layers, p)
+			}
+		}
+		return layers
+	}
+
+	dir := filepath.Dir(configPath)
+	for _, name := range overlayDirNames {
+		entries, err := os.ReadDir(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var layers []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if ext := filepath.Ext(entry.Name()); ext == ".yaml" || ext == ".yml" {
+				layers = append(layers, filepath.Join(dir, name, entry.Name()))
+			}
+		}
+		sort.Strings(layers)
+		return layers
+	}
+	return nil
+}
+
+// envOverlayPath returns the LF_ENV-specific overlay file next to
+// configPath, or "" if LF_ENV isn't set or no such file exists.
+func envOverlayPath(configPath string) string {
+	env := os.Getenv(envSelector)
+	if env == "" {
+		return ""
+	}
+	ext := filepath.Ext(configPath)
+	candidate := strings.TrimSuffix(configPath, ext) + "." + env + ext
+	if _, err := os.Stat(candidate); err != nil {
+		return ""
+	}
+	return candidate
+}
+
+// loadConfigLayer reads and parses one overlay YAML file for mergeConfig to
+// fold into the base config.
+func loadConfigLayer(path string) (*LlamaFarmConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config layer %s: %w", path, err)
+	}
+	var layer LlamaFarmConfig
+	if err := yaml.Unmarshal(data, &layer); err != nil {
+		return nil, fmt.Errorf("failed to parse config layer %s: %w", path, err)
+	}
+	return &layer, nil
+}
+
+// mergeConfig folds overlay into base and returns base: top-level scalars
+// are overridden when overlay sets them, the RAG component maps are deep-
+// merged key by key, and Datasets/Prompts/Models/Registries are appended
+// with dedupe, where an overlay entry with the same identity replaces the
+// matching base entry instead of duplicating it.
+func mergeConfig(base, overlay *LlamaFarmConfig) *LlamaFarmConfig {
+	if overlay.Version != "" {
+		base.Version = overlay.Version
+	}
+	if overlay.Name != "" {
+		base.Name = overlay.Name
+	}
+	base.RAG = mergeRAGConfig(base.RAG, overlay.RAG)
+	base.Datasets = mergeDatasets(base.Datasets, overlay.Datasets)
+	base.Prompts = mergePrompts(base.Prompts, overlay.Prompts)
+	base.Models = mergeModels(base.Models, overlay.Models)
+	base.Registries = mergeRegistries(base.Registries, overlay.Registries)
+	return base
+}
+
+func mergeRAGConfig(base, overlay RAGConfig) RAGConfig {
+	if overlay.Description != "" {
+		base.Description = overlay.Description
+	}
+	base.Parsers = mergeParserMap(base.Parsers, overlay.Parsers)
+	base.Embedders = mergeEmbedderMap(base.Embedders, overlay.Embedders)
+	base.VectorStores = mergeVectorStoreMap(base.VectorStores, overlay.VectorStores)
+	base.RetrievalStrategies = mergeRetrievalStrategyMap(base.RetrievalStrategies, overlay.RetrievalStrategies)
+
+	if overlay.Defaults.Parser != "" {
+		base.Defaults.Parser = overlay.Defaults.Parser
+	}
+	if overlay.Defaults.Embedder != "" {
+		base.Defaults.Embedder = overlay.Defaults.Embedder
+	}
+	if overlay.Defaults.VectorStore != "" {
+		base.Defaults.VectorStore = overlay.Defaults.VectorStore
+	}
+	if overlay.Defaults.RetrievalStrategy != "" {
+		base.Defaults.RetrievalStrategy = overlay.Defaults.RetrievalStrategy
+	}
+
+	if overlay.Parser.Type != "" {
+		base.Parser = overlay.Parser
+	}
+	if overlay.Embedder.Type != "" {
+		base.Embedder = overlay.Embedder
+	}
+	if overlay.VectorStore.Type != "" {
+		base.VectorStore = overlay.VectorStore
+	}
+	return base
+}
+
+func mergeParserMap(base, overlay map[string]ParserConfig) map[string]ParserConfig {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]ParserConfig{}
+	}
+	for name, cfg := range overlay {
+		existing, ok := base[name]
+		if !ok {
+			base[name] = cfg
+			continue
+		}
+		if cfg.Type != "" {
+			existing.Type = cfg.Type
+		}
+		existing.Config = deepMergeMap(existing.Config, cfg.Config)
+		if len(cfg.FileExtensions) > 0 {
+			existing.FileExtensions = cfg.FileExtensions
+		}
+		if len(cfg.MimeTypes) > 0 {
+			existing.MimeTypes = cfg.MimeTypes
+		}
+		if cfg.Priority != 0 {
+			existing.Priority = cfg.Priority
+		}
+		base[name] = existing
+	}
+	return base
+}
+
+func mergeEmbedderMap(base, overlay map[string]EmbedderConfig) map[string]EmbedderConfig {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]EmbedderConfig{}
+	}
+	for name, cfg := range overlay {
+		existing, ok := base[name]
+		if !ok {
+			base[name] = cfg
+			continue
+		}
+		if cfg.Type != "" {
+			existing.Type = cfg.Type
+		}
+		existing.Config = deepMergeMap(existing.Config, cfg.Config)
+		base[name] = existing
+	}
+	return base
+}
+
+func mergeVectorStoreMap(base, overlay map[string]VectorStoreConfig) map[string]VectorStoreConfig {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]VectorStoreConfig{}
+	}
+	for name, cfg := range overlay {
+		existing, ok := base[name]
+		if !ok {
+			base[name] = cfg
+			continue
+		}
+		if cfg.Type != "" {
+			existing.Type = cfg.Type
+		}
+		existing.Config = deepMergeMap(existing.Config, cfg.Config)
+		base[name] = existing
+	}
+	return base
+}
+
+func mergeRetrievalStrategyMap(base, overlay map[string]RetrievalStrategyConfig) map[string]RetrievalStrategyConfig {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]RetrievalStrategyConfig{}
+	}
+	for name, cfg := range overlay {
+		existing, ok := base[name]
+		if !ok {
+			base[name] = cfg
+			continue
+		}
+		if cfg.Type != "" {
+			existing.Type = cfg.Type
+		}
+		if cfg.Description != "" {
+			existing.Description = cfg.Description
+		}
+		existing.Config = deepMergeMap(existing.Config, cfg.Config)
+		base[name] = existing
+	}
+	return base
+}
+
+// deepMergeMap recursively folds overlay into base, keyed by map, so that a
+// layer only needs to specify the keys it's changing. yaml.v2 unmarshals
+// nested maps into map[interface{}]interface{} rather than
+// map[string]interface{}, so both are normalized via asStringMap before
+// recursing.
+func deepMergeMap(base, overlay map[string]interface{}) map[string]interface{} {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for key, overlayVal := range overlay {
+		if baseVal, ok := base[key]; ok {
+			if baseMap, ok := asStringMap(baseVal); ok {
+				if overlayMap, ok := asStringMap(overlayVal); ok {
+					base[key] = deepMergeMap(baseMap, overlayMap)
+					continue
+				}
+			}
+		}
+		base[key] = overlayVal
+	}
+	return base
+}
+
+// asStringMap normalizes either map shape yaml.v2 might produce into a
+// map[string]interface{}, or reports false if v isn't a map at all.
+func asStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			out[fmt.Sprintf("%v", k)] = val
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func mergeDatasets(base, overlay []Dataset) []Dataset {
+	for _, d := range overlay {
+		replaced := false
+		for i, existing := range base {
+			if existing.Name == d.Name {
+				base[i] = d
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, d)
+		}
+	}
+	return base
+}
+
+func mergePrompts(base, overlay []Prompt) []Prompt {
+	for _, p := range overlay {
+		if p.Name == "" {
+			base = append(base, p)
+			continue
+		}
+		replaced := false
+		for i, existing := range base {
+			if existing.Name == p.Name {
+				base[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, p)
+		}
+	}
+	return base
+}
+
+func mergeModels(base, overlay []Model) []Model {
+	key := func(m Model) string { return m.Provider + "/" + m.Model }
+	for _, m := range overlay {
+		replaced := false
+		for i, existing := range base {
+			if key(existing) == key(m) {
+				base[i] = m
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, m)
+		}
+	}
+	return base
+}
+
+func mergeRegistries(base, overlay []DatasetRegistry) []DatasetRegistry {
+	for _, r := range overlay {
+		replaced := false
+		for i, existing := range base {
+			if existing.Name == r.Name {
+				base[i] = r
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, r)
+		}
+	}
+	return base
+}
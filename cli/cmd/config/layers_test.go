@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeLayerFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadConfig_MergesConfDLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := writeLayerFile(t, dir, "llamafarm.yaml", `
+version: v1
+name: acme/shop
+rag:
+  parsers:
+    pdf:
+      type: pdf
+      config:
+        dpi: 150
+`)
+	writeLayerFile(t, dir, "llamafarm.d/10-datasets.yaml", `
+datasets:
+  - name: docs
+    files: ["docs/*.md"]
+`)
+	writeLayerFile(t, dir, "llamafarm.d/20-overrides.yaml", `
+rag:
+  parsers:
+    pdf:
+      config:
+        dpi: 300
+`)
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Datasets) != 1 || cfg.Datasets[0].Name != "docs" {
+		t.Fatalf("expected the conf.d dataset to be merged in, got: %+v", cfg.Datasets)
+	}
+	pdf := cfg.RAG.Parsers["pdf"]
+	if pdf.Type != "pdf" {
+		t.Fatalf("expected the base parser type to survive the merge, got: %+v", pdf)
+	}
+	if dpi := pdf.Config["dpi"]; dpi != 300 {
+		t.Fatalf("expected the later layer's dpi to win, got: %v", dpi)
+	}
+}
+
+func TestLoadConfig_LFConfigLayersOverridesConfD(t *testing.T) {
+	dir := t.TempDir()
+	base := writeLayerFile(t, dir, "llamafarm.yaml", "version: v1\nname: acme/shop\n")
+	writeLayerFile(t, dir, "llamafarm.d/ignored.yaml", "name: acme/ignored\n")
+	explicit := writeLayerFile(t, dir, "explicit.yaml", "name: acme/explicit\n")
+
+	t.Setenv(envConfigLayers, explicit)
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Name != "acme/explicit" {
+		t.Fatalf("expected LF_CONFIG_LAYERS to take priority over llamafarm.d, got name: %s", cfg.Name)
+	}
+}
+
+func TestLoadConfig_LFEnvOverlay(t *testing.T) {
+	dir := t.TempDir()
+	base := writeLayerFile(t, dir, "llamafarm.yaml", "version: v1\nname: acme/shop\n")
+	writeLayerFile(t, dir, "llamafarm.dev.yaml", "name: acme/shop-dev\n")
+
+	t.Setenv(envSelector, "dev")
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Name != "acme/shop-dev" {
+		t.Fatalf("expected the LF_ENV overlay to win, got name: %s", cfg.Name)
+	}
+}
+
+func TestMergeDatasets_DedupesByName(t *testing.T) {
+	base := []Dataset{{Name: "docs", Files: []string{"a.md"}}}
+	overlay := []Dataset{
+		{Name: "docs", Files: []string{"b.md"}},
+		{Name: "extra", Files: []string{"c.md"}},
+	}
+	merged := mergeDatasets(base, overlay)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 datasets after dedupe, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].Files[0] != "b.md" {
+		t.Fatalf("expected the overlay entry to replace the base entry, got: %+v", merged[0])
+	}
+}
+
+func TestMergeModels_DedupesByProviderAndModel(t *testing.T) {
+	base := []Model{{Provider: "openai", Model: "gpt-4"}}
+	overlay := []Model{
+		{Provider: "openai", Model: "gpt-4"},
+		{Provider: "anthropic", Model: "claude"},
+	}
+	merged := mergeModels(base, overlay)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 models after dedupe, got %d: %+v", len(merged), merged)
+	}
+}
+
+func TestDeepMergeMap_RecursesNestedMaps(t *testing.T) {
+	base := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 2},
+		"b": "base",
+	}
+	overlay := map[string]interface{}{
+		"a": map[string]interface{}{"y": 20, "z": 30},
+		"c": "new",
+	}
+	merged := deepMergeMap(base, overlay)
+	nested, ok := merged["a"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested map to remain a map, got: %+v", merged["a"])
+	}
+	if nested["x"] != 1 || nested["y"] != 20 || nested["z"] != 30 {
+		t.Fatalf("unexpected nested merge result: %+v", nested)
+	}
+	if merged["b"] != "base" || merged["c"] != "new" {
+		t.Fatalf("unexpected top-level merge result: %+v", merged)
+	}
+}
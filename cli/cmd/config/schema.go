@@ -4,6 +4,7 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -13,8 +14,9 @@ import (
 //go:embed schema.yaml
 var configSchema embed.FS
 
-// Schema represents a basic JSON/YAML schema structure.
-// It's simplified to focus on the generation logic.
+// Schema represents a JSON-Schema-like structure used both to generate a
+// starter llamafarm.yaml and to validate an existing one. It only covers
+// the subset of JSON Schema this project's schema.yaml actually uses.
 type Schema struct {
 	Type        string             `yaml:"type"`
 	Properties  map[string]*Schema `yaml:"properties,omitempty"`
@@ -23,11 +25,54 @@ type Schema struct {
 	Default     any                `yaml:"default,omitempty"`
 	Example     any                `yaml:"example,omitempty"`
 	Description string             `yaml:"description,omitempty"`
+
+	Enum                 []any     `yaml:"enum,omitempty"`
+	OneOf                []*Schema `yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema `yaml:"anyOf,omitempty"`
+	AllOf                []*Schema `yaml:"allOf,omitempty"`
+	Minimum              *float64  `yaml:"minimum,omitempty"`
+	Maximum              *float64  `yaml:"maximum,omitempty"`
+	Pattern              string    `yaml:"pattern,omitempty"`
+	Format               string    `yaml:"format,omitempty"`
+	AdditionalProperties *bool     `yaml:"additionalProperties,omitempty"`
+	Ref                  string    `yaml:"$ref,omitempty"`
+}
+
+// resolveRef follows a local "#/a/b/c" JSON-pointer-style $ref against the
+// root schema, looking up each segment as a property name (root.properties.a,
+// then its properties.b, and so on); segments such as "properties" or
+// "definitions" are not treated as keywords. Only same-document refs are
+// supported.
+func resolveRef(root, s *Schema) (*Schema, error) {
+	if s.Ref == "" {
+		return s, nil
+	}
+	if !strings.HasPrefix(s.Ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local refs starting with '#/' are supported", s.Ref)
+	}
+	cur := root
+	for _, part := range strings.Split(strings.TrimPrefix(s.Ref, "#/"), "/") {
+		if cur.Properties == nil {
+			return nil, fmt.Errorf("$ref %q: %q has no properties", s.Ref, part)
+		}
+		next, ok := cur.Properties[part]
+		if !ok {
+			return nil, fmt.Errorf("$ref %q: %q not found", s.Ref, part)
+		}
+		cur = next
+	}
+	return cur, nil
 }
 
 // generateFromSchema recursively generates a Go map from a given schema.
-// This map can then be marshaled into a YAML file.
-func generateFromSchema(schema *Schema) (interface{}, error) {
+// This map can then be marshaled into a YAML file. root is the document
+// root, used to resolve local $ref pointers.
+func generateFromSchema(root, schema *Schema) (interface{}, error) {
+	schema, err := resolveRef(root, schema)
+	if err != nil {
+		return nil, err
+	}
+
 	// Priority 1: Use the default value if provided.
 	if schema.Default != nil {
 		return schema.Default, nil
@@ -38,7 +83,17 @@ func generateFromSchema(schema *Schema) (interface{}, error) {
 		return schema.Example, nil
 	}
 
-	// Priority 3: Generate based on the type.
+	// Priority 3: Honour enum by emitting the first listed value.
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0], nil
+	}
+
+	// Priority 4: for oneOf/anyOf, generate a sample from the first branch.
+	if branches := firstOf(schema); branches != nil {
+		return generateFromSchema(root, branches)
+	}
+
+	// Priority 5: Generate based on the type.
 	switch schema.Type {
 	case "object":
 		// For objects, iterate through required properties and generate a value for each.
@@ -47,7 +102,7 @@ func generateFromSchema(schema *Schema) (interface{}, error) {
 			return obj, nil // Return empty object if no properties are defined
 		}
 		for _, key := range schema.Required {
-			val, err := generateFromSchema(schema.Properties[key])
+			val, err := generateFromSchema(root, schema.Properties[key])
 			if err != nil {
 				return nil, fmt.Errorf("error generating property '%s': %w", key, err)
 			}
@@ -60,7 +115,7 @@ func generateFromSchema(schema *Schema) (interface{}, error) {
 		if schema.Items == nil {
 			return []any{}, nil // Return empty array if 'items' is not defined
 		}
-		item, err := generateFromSchema(schema.Items)
+		item, err := generateFromSchema(root, schema.Items)
 		if err != nil {
 			return nil, fmt.Errorf("error generating array item: %w", err)
 		}
@@ -83,21 +138,40 @@ func generateFromSchema(schema *Schema) (interface{}, error) {
 	}
 }
 
-func Generate() (string, error) {
+// firstOf returns the first oneOf/anyOf branch declared on a schema, or nil
+// if it has none. allOf isn't branch-like (every branch applies at once) so
+// it's handled separately during validation instead.
+func firstOf(schema *Schema) *Schema {
+	if len(schema.OneOf) > 0 {
+		return schema.OneOf[0]
+	}
+	if len(schema.AnyOf) > 0 {
+		return schema.AnyOf[0]
+	}
+	return nil
+}
+
+// loadRootSchema reads and parses the embedded schema.yaml.
+func loadRootSchema() (*Schema, error) {
 	schemaData, err := configSchema.ReadFile("schema.yaml")
 	if err != nil {
-		log.Fatalf("Failed to read schema file '%s': %v", "schema.yaml", err)
+		return nil, fmt.Errorf("failed to read schema file 'schema.yaml': %w", err)
 	}
-
-	// Unmarshal the YAML schema into our Go struct.
 	var schema Schema
-	err = yaml.Unmarshal(schemaData, &schema)
+	if err := yaml.Unmarshal(schemaData, &schema); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema YAML: %w", err)
+	}
+	return &schema, nil
+}
+
+func Generate() (string, error) {
+	schema, err := loadRootSchema()
 	if err != nil {
-		log.Fatalf("Failed to unmarshal schema YAML: %v", err)
+		log.Fatalf("%v", err)
 	}
 
 	// Generate the data structure from the schema.
-	generatedData, err := generateFromSchema(&schema)
+	generatedData, err := generateFromSchema(schema, schema)
 	if err != nil {
 		log.Fatalf("Failed to generate data from schema: %v", err)
 	}
@@ -6,17 +6,29 @@ import (
 	"path/filepath"
 	"strings"
 
+	"llamafarm-cli/internal/errs"
+
 	"gopkg.in/yaml.v2"
 )
 
 // LlamaFarmConfig represents the complete llamafarm.yaml configuration
 type LlamaFarmConfig struct {
-	Version  string     `yaml:"version"`
-	Name     string     `yaml:"name,omitempty"`
-	Prompts  []Prompt   `yaml:"prompts,omitempty"`
-	RAG      RAGConfig  `yaml:"rag,omitempty"`
-	Datasets []Dataset  `yaml:"datasets,omitempty"`
-	Models   []Model    `yaml:"models,omitempty"`
+	Version    string            `yaml:"version"`
+	Name       string            `yaml:"name,omitempty"`
+	Prompts    []Prompt          `yaml:"prompts,omitempty"`
+	RAG        RAGConfig         `yaml:"rag,omitempty"`
+	Datasets   []Dataset         `yaml:"datasets,omitempty"`
+	Models     []Model           `yaml:"models,omitempty"`
+	Registries []DatasetRegistry `yaml:"registries,omitempty"`
+}
+
+// DatasetRegistry is a remote source `lf datasets pull`/`push` resolve
+// dataset artifacts against, in addition to the local ~/.llamafarm/datasets/
+// cache, analogous to a Helm chart repository.
+type DatasetRegistry struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	Type string `yaml:"type"` // "oci" or "http"
 }
 
 // Dataset represents a dataset configuration
@@ -99,18 +111,34 @@ func LoadConfig(configPath string) (*LlamaFarmConfig, error) {
 		} else if _, err := os.Stat("llamafarm.yml"); err == nil {
 			configPath = "llamafarm.yml"
 		} else {
-			return nil, fmt.Errorf("no llamafarm.yaml file found in current directory")
+			return nil, errs.New(errs.ErrConfigInvalid, fmt.Errorf("no llamafarm.yaml file found in current directory"))
 		}
 	}
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, errs.New(errs.ErrConfigInvalid, fmt.Errorf("failed to read config file: %w", err))
 	}
 
 	var config LlamaFarmConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, errs.New(errs.ErrConfigInvalid, fmt.Errorf("failed to parse config file: %w", err))
+	}
+
+	for _, layerPath := range resolveConfigLayers(configPath) {
+		layer, err := loadConfigLayer(layerPath)
+		if err != nil {
+			return nil, errs.New(errs.ErrConfigInvalid, err)
+		}
+		mergeConfig(&config, layer)
+	}
+
+	if envPath := envOverlayPath(configPath); envPath != "" {
+		layer, err := loadConfigLayer(envPath)
+		if err != nil {
+			return nil, errs.New(errs.ErrConfigInvalid, err)
+		}
+		mergeConfig(&config, layer)
 	}
 
 	return &config, nil
@@ -175,6 +203,53 @@ func (c *LlamaFarmConfig) RemoveDataset(name string) error {
 	return nil
 }
 
+// AddPrompt adds a new named prompt to the configuration
+func (c *LlamaFarmConfig) AddPrompt(prompt Prompt) error {
+	for _, p := range c.Prompts {
+		if p.Name == prompt.Name {
+			return fmt.Errorf("prompt with name '%s' already exists", prompt.Name)
+		}
+	}
+	c.Prompts = append(c.Prompts, prompt)
+	return nil
+}
+
+// AddParser adds a new named parser to the RAG configuration
+func (c *LlamaFarmConfig) AddParser(name string, parser ParserConfig) error {
+	if _, exists := c.RAG.Parsers[name]; exists {
+		return fmt.Errorf("parser with name '%s' already exists", name)
+	}
+	if c.RAG.Parsers == nil {
+		c.RAG.Parsers = map[string]ParserConfig{}
+	}
+	c.RAG.Parsers[name] = parser
+	return nil
+}
+
+// AddEmbedder adds a new named embedder to the RAG configuration
+func (c *LlamaFarmConfig) AddEmbedder(name string, embedder EmbedderConfig) error {
+	if _, exists := c.RAG.Embedders[name]; exists {
+		return fmt.Errorf("embedder with name '%s' already exists", name)
+	}
+	if c.RAG.Embedders == nil {
+		c.RAG.Embedders = map[string]EmbedderConfig{}
+	}
+	c.RAG.Embedders[name] = embedder
+	return nil
+}
+
+// AddVectorStore adds a new named vector store to the RAG configuration
+func (c *LlamaFarmConfig) AddVectorStore(name string, vectorStore VectorStoreConfig) error {
+	if _, exists := c.RAG.VectorStores[name]; exists {
+		return fmt.Errorf("vector store with name '%s' already exists", name)
+	}
+	if c.RAG.VectorStores == nil {
+		c.RAG.VectorStores = map[string]VectorStoreConfig{}
+	}
+	c.RAG.VectorStores[name] = vectorStore
+	return nil
+}
+
 // ProjectInfo represents extracted namespace and project information
 type ProjectInfo struct {
 	Namespace string
@@ -222,7 +297,7 @@ func GetServerConfig(configPath string, serverURL string, namespace string, proj
 	if configPath != "" {
 		config, err = LoadConfig(configPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load config: %w", err)
+			return nil, errs.New(errs.ErrConfigInvalid, fmt.Errorf("failed to load config: %w", err))
 		}
 	} else {
 		// Try to load from default locations
@@ -253,10 +328,10 @@ func GetServerConfig(configPath string, serverURL string, namespace string, proj
 
 	// Validate required fields
 	if finalNamespace == "" {
-		return nil, fmt.Errorf("namespace is required (provide via --namespace flag or set 'name' in llamafarm.yaml)")
+		return nil, errs.New(errs.ErrUser, fmt.Errorf("namespace is required (provide via --namespace flag or set 'name' in llamafarm.yaml)"))
 	}
 	if finalProject == "" {
-		return nil, fmt.Errorf("project is required (provide via --project flag or set 'name' in llamafarm.yaml)")
+		return nil, errs.New(errs.ErrUser, fmt.Errorf("project is required (provide via --project flag or set 'name' in llamafarm.yaml)"))
 	}
 
 	return &ServerConfig{
@@ -275,7 +350,7 @@ func GetServerConfigLenient(configPath string, serverURL string, namespace strin
     if configPath != "" {
         cfg, err = LoadConfig(configPath)
         if err != nil {
-            return nil, fmt.Errorf("failed to load config: %w", err)
+            return nil, errs.New(errs.ErrConfigInvalid, fmt.Errorf("failed to load config: %w", err))
         }
     } else {
         cfg, _ = LoadConfig("")
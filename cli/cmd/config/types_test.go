@@ -17,22 +17,22 @@ func writeTempConfig(t *testing.T, content string) string {
 }
 
 func TestGetProjectInfo(t *testing.T) {
-    // Explicit fields only
-    cfg := &LlamaFarmConfig{Name: "shop", Namespace: "acme"}
+    // Name in "namespace/project" form
+    cfg := &LlamaFarmConfig{Name: "acme/shop"}
     pi, err := cfg.GetProjectInfo()
     if err != nil { t.Fatalf("unexpected err: %v", err) }
     if pi.Namespace != "acme" || pi.Project != "shop" { t.Fatalf("unexpected project info: %+v", pi) }
 
-    // Negative: both missing
+    // Negative: name missing
     cfg = &LlamaFarmConfig{}
     if _, err := cfg.GetProjectInfo(); err == nil {
-        t.Fatalf("expected error when both name and namespace are missing")
+        t.Fatalf("expected error when name is missing")
     }
 
-    // Negative: explicit with slash in name
-    cfg = &LlamaFarmConfig{Name: "acme/shop", Namespace: "acme"}
+    // Negative: name without a namespace/project split
+    cfg = &LlamaFarmConfig{Name: "shop"}
     if _, err := cfg.GetProjectInfo(); err == nil {
-        t.Fatalf("expected error for explicit fields when name contains slash")
+        t.Fatalf("expected error when name has no namespace/project separator")
     }
 }
 
@@ -43,8 +43,8 @@ func TestGetServerConfig_Strict(t *testing.T) {
         t.Fatalf("expected error when namespace/project missing")
     }
 
-    // With config file containing explicit fields
-    path := writeTempConfig(t, "name: shop\nnamespace: acme\nversion: v1\n")
+    // With config file containing a namespace/project name
+    path := writeTempConfig(t, "name: acme/shop\nversion: v1\n")
     sc, err := GetServerConfig(path, "", "", "")
     if err != nil {
         t.Fatalf("unexpected err: %v", err)
@@ -65,7 +65,7 @@ func TestGetServerConfig_Lenient(t *testing.T) {
     }
 
     // With config file and overrides
-    path := writeTempConfig(t, "name: shop\nnamespace: acme\nversion: v1\n")
+    path := writeTempConfig(t, "name: acme/shop\nversion: v1\n")
     sc, err = GetServerConfigLenient(path, "http://x", "ns", "proj")
     if err != nil {
         t.Fatalf("unexpected err: %v", err)
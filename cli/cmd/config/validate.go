@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ValidationError is one schema violation found in a user config, with a
+// JSON-pointer-style path so editors/CI output can point at the exact spot.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate loads the user YAML file at path and checks it against the
+// embedded schema.yaml, returning every violation found (not just the
+// first) so users can fix a config in one pass.
+func Validate(path string) []ValidationError {
+	root, err := loadRootSchema()
+	if err != nil {
+		return []ValidationError{{Path: "$", Message: err.Error()}}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []ValidationError{{Path: "$", Message: fmt.Sprintf("failed to read %s: %v", path, err)}}
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []ValidationError{{Path: "$", Message: fmt.Sprintf("failed to parse YAML: %v", err)}}
+	}
+
+	var errs []ValidationError
+	validateValue(root, root, doc, "$", &errs)
+	return errs
+}
+
+func validateValue(root, schema *Schema, value any, path string, errs *[]ValidationError) {
+	schema, err := resolveRef(root, schema)
+	if err != nil {
+		*errs = append(*errs, ValidationError{Path: path, Message: err.Error()})
+		return
+	}
+
+	// allOf: every branch must validate independently.
+	for _, branch := range schema.AllOf {
+		validateValue(root, branch, value, path, errs)
+	}
+
+	// oneOf/anyOf: at least one branch must validate cleanly.
+	if branches := append(append([]*Schema{}, schema.OneOf...), schema.AnyOf...); len(branches) > 0 {
+		ok := false
+		for _, branch := range branches {
+			var sub []ValidationError
+			validateValue(root, branch, value, path, &sub)
+			if len(sub) == 0 {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "value does not match any allowed branch"})
+		}
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("value %v is not one of the allowed values %v", value, schema.Enum)})
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		m, ok := toStringMap(value)
+		if !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "expected an object"})
+			return
+		}
+		for _, req := range schema.Required {
+			if _, present := m[req]; !present {
+				*errs = append(*errs, ValidationError{Path: path + "/" + req, Message: "required property is missing"})
+			}
+		}
+		for key, val := range m {
+			propSchema, known := schema.Properties[key]
+			if !known {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					*errs = append(*errs, ValidationError{Path: path + "/" + key, Message: "additional property is not allowed"})
+				}
+				continue
+			}
+			validateValue(root, propSchema, val, path+"/"+key, errs)
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "expected an array"})
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				validateValue(root, schema.Items, item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "expected a string"})
+			return
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(s) {
+				*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("value %q does not match pattern %q", s, schema.Pattern)})
+			}
+		}
+		if schema.Format != "" {
+			if validateFormat, known := stringFormats[schema.Format]; known && !validateFormat(s) {
+				*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("value %q is not a valid %q", s, schema.Format)})
+			}
+		}
+
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "expected a number"})
+			return
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("value %v is below minimum %v", n, *schema.Minimum)})
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("value %v is above maximum %v", n, *schema.Maximum)})
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, ValidationError{Path: path, Message: "expected a boolean"})
+		}
+	}
+}
+
+// uuidPattern matches the canonical 8-4-4-4-12 hex UUID representation.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// stringFormats maps the JSON-Schema "format" values this project's
+// schema.yaml is expected to use to a validator for that format. An
+// unrecognized format is left unchecked rather than rejected, since "format"
+// is an annotation in JSON Schema, not a hard constraint.
+var stringFormats = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"email": func(s string) bool {
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	},
+	"uuid": uuidPattern.MatchString,
+}
+
+func enumContains(enum []any, value any) bool {
+	for _, e := range enum {
+		if fmt.Sprintf("%v", e) == fmt.Sprintf("%v", value) {
+			return true
+		}
+	}
+	return false
+}
+
+// toStringMap normalizes the map[interface{}]interface{} that yaml.v2
+// produces into map[string]any so lookups by property name work.
+func toStringMap(value any) (map[string]any, bool) {
+	switch m := value.(type) {
+	case map[string]any:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[fmt.Sprintf("%v", k)] = v
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempYAML(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "llamafarm.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return path
+}
+
+func TestValidate_Valid(t *testing.T) {
+	path := writeTempYAML(t, `
+version: v1
+name: my-org/my-project
+models:
+  - provider: openai
+    model: gpt-4
+`)
+	if errs := Validate(path); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidate_MissingRequired(t *testing.T) {
+	path := writeTempYAML(t, `name: my-org/my-project`)
+	errs := Validate(path)
+	if len(errs) != 1 || errs[0].Path != "$/version" {
+		t.Fatalf("expected a single missing-required error at $/version, got %v", errs)
+	}
+}
+
+func TestValidateValue_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		value   string
+		wantErr bool
+	}{
+		{"valid_email", "email", "user@example.com", false},
+		{"invalid_email", "email", "not-an-email", true},
+		{"valid_date_time", "date-time", "2026-07-30T00:00:00Z", false},
+		{"invalid_date_time", "date-time", "not-a-date", true},
+		{"valid_uri", "uri", "https://example.com/path", false},
+		{"invalid_uri", "uri", "not a uri", true},
+		{"valid_uuid", "uuid", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"invalid_uuid", "uuid", "not-a-uuid", true},
+		{"unknown_format_is_unchecked", "made-up-format", "anything", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := &Schema{Type: "string", Format: tc.format}
+			var errs []ValidationError
+			validateValue(schema, schema, tc.value, "$", &errs)
+			if tc.wantErr && len(errs) == 0 {
+				t.Fatalf("expected a format error for %q against format %q", tc.value, tc.format)
+			}
+			if !tc.wantErr && len(errs) != 0 {
+				t.Fatalf("expected no errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidate_PatternAndEnum(t *testing.T) {
+	path := writeTempYAML(t, `
+version: v1
+name: not-a-valid-name
+models:
+  - provider: azure
+    model: gpt-4
+`)
+	errs := Validate(path)
+	if len(errs) != 2 {
+		t.Fatalf("expected a pattern error and an enum error, got %v", errs)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"llamafarm-cli/cmd/config"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// configCmd groups commands that operate on llamafarm.yaml itself, as
+// opposed to the projects/datasets commands that talk to a running server.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Generate and validate llamafarm.yaml configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("LlamaFarm Config")
+		cmd.Help()
+	},
+}
+
+var configGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Print a starter llamafarm.yaml generated from the config schema",
+	Long:  `Generates a sample llamafarm.yaml from the embedded schema, filling in defaults, examples, or placeholder values for every required field.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := config.Generate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(out)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a llamafarm.yaml against the config schema",
+	Long:  `Validates a llamafarm.yaml file against the embedded schema and reports every violation found, with a JSON-pointer-style path to each.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "llamafarm.yaml"
+		if len(args) > 0 {
+			path = args[0]
+		}
+		errs := config.Validate(path)
+		if len(errs) == 0 {
+			fmt.Printf("%s is valid.\n", path)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s has %d issue(s):\n", path, len(errs))
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "  %s\n", e.Error())
+		}
+		os.Exit(1)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGenerateCmd)
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// printEffectiveConfig loads configPath (or the default llamafarm.yaml/.yml
+// in the current directory when empty) with every llamafarm.d/conf.d and
+// LF_ENV layer merged in, and prints the result as YAML. This is what
+// backs --print-config on init/run, so users can see exactly what the
+// layered config resolves to without guessing at merge order.
+func printEffectiveConfig(configPath string) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal effective config: %w", err)
+	}
+	fmt.Print(string(out))
+	return nil
+}
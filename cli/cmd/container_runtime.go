@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// containerRuntime abstracts the CLI differences between Docker, Podman, and
+// nerdctl so the server auto-start path can work on machines that only have
+// one of the non-Docker engines installed. Podman and nerdctl are largely
+// drop-in compatible with the Docker CLI for the subset of commands we use
+// (pull/run/start/ps), so the default methods below just shell out to the
+// runtime's binary; only the handful of genuinely divergent behaviors are
+// overridden per runtime.
+type containerRuntime interface {
+	// Name is the human-readable runtime name, used in log/error messages.
+	Name() string
+	// Binary is the CLI executable to invoke (e.g. "docker", "podman").
+	Binary() string
+	// Available reports whether the runtime's CLI is usable.
+	Available() error
+	// HostGatewayHost returns the hostname containers should use to reach
+	// services on the host (docker uses host.docker.internal, podman uses
+	// host.containers.internal).
+	HostGatewayHost() string
+	// ExtraRunArgs returns additional `run` flags this runtime needs, such
+	// as rootless Podman's slirp4netns networking mode.
+	ExtraRunArgs() []string
+	// EnsureReady prepares the runtime's backend for use, e.g. starting the
+	// Podman machine VM on macOS. Most runtimes have nothing to do here.
+	EnsureReady() error
+	// NormalizeImage adjusts an image reference for this runtime's default
+	// registry resolution, e.g. qualifying Docker Hub images with the
+	// "docker.io/" prefix for runtimes that don't assume it.
+	NormalizeImage(image string) string
+}
+
+type dockerRuntime struct{}
+
+func (dockerRuntime) Name() string   { return "Docker" }
+func (dockerRuntime) Binary() string { return "docker" }
+func (dockerRuntime) Available() error {
+	if err := exec.Command("docker", "--version").Run(); err != nil {
+		return errNotAvailable("Docker")
+	}
+	return nil
+}
+func (dockerRuntime) HostGatewayHost() string            { return "host.docker.internal" }
+func (dockerRuntime) ExtraRunArgs() []string             { return nil }
+func (dockerRuntime) EnsureReady() error                 { return nil }
+func (dockerRuntime) NormalizeImage(image string) string { return image }
+
+type podmanRuntime struct{}
+
+func (podmanRuntime) Name() string   { return "Podman" }
+func (podmanRuntime) Binary() string { return "podman" }
+func (podmanRuntime) Available() error {
+	if err := exec.Command("podman", "--version").Run(); err != nil {
+		return errNotAvailable("Podman")
+	}
+	return nil
+}
+func (podmanRuntime) HostGatewayHost() string { return "host.containers.internal" }
+func (podmanRuntime) ExtraRunArgs() []string {
+	// Rootless Podman needs slirp4netns for outbound networking on many
+	// distros where the default netavark/CNI setup isn't configured.
+	if os.Geteuid() != 0 {
+		return []string{"--network", "slirp4netns"}
+	}
+	return nil
+}
+
+// EnsureReady starts the Podman machine VM on macOS if it isn't already
+// running. On Linux, Podman talks to the host kernel directly and there's
+// no VM to start, so this is a no-op there.
+func (podmanRuntime) EnsureReady() error {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	out, err := exec.Command("podman", "machine", "list", "--format", "{{.Running}}").Output()
+	if err != nil {
+		// No machine configured; nothing for us to start, let the actual
+		// podman command surface whatever error applies.
+		return nil
+	}
+	if strings.Contains(string(out), "true") {
+		return nil
+	}
+	startCmd := exec.Command("podman", "machine", "start")
+	startCmd.Stdout = os.Stdout
+	startCmd.Stderr = os.Stderr
+	if err := startCmd.Run(); err != nil {
+		return fmt.Errorf("failed to start podman machine: %w", err)
+	}
+	return nil
+}
+
+// NormalizeImage qualifies unqualified image references with "docker.io/"
+// since Podman, unlike Docker, often isn't configured with Docker Hub as an
+// unqualified-search registry and will otherwise prompt interactively or
+// fail outright.
+func (podmanRuntime) NormalizeImage(image string) string {
+	if strings.Contains(image, "/") {
+		firstSegment := strings.SplitN(image, "/", 2)[0]
+		if strings.Contains(firstSegment, ".") || strings.Contains(firstSegment, ":") || firstSegment == "localhost" {
+			return image
+		}
+	}
+	return "docker.io/" + image
+}
+
+type nerdctlRuntime struct{}
+
+func (nerdctlRuntime) Name() string   { return "nerdctl" }
+func (nerdctlRuntime) Binary() string { return "nerdctl" }
+func (nerdctlRuntime) Available() error {
+	if err := exec.Command("nerdctl", "--version").Run(); err != nil {
+		return errNotAvailable("nerdctl")
+	}
+	return nil
+}
+func (nerdctlRuntime) HostGatewayHost() string            { return "host.docker.internal" }
+func (nerdctlRuntime) ExtraRunArgs() []string             { return nil }
+func (nerdctlRuntime) EnsureReady() error                 { return nil }
+func (nerdctlRuntime) NormalizeImage(image string) string { return image }
+
+func errNotAvailable(name string) error {
+	return fmt.Errorf("%s is not available on PATH", name)
+}
+
+// detectContainerRuntime picks the container runtime to use. LF_CONTAINER_RUNTIME
+// forces a specific choice; otherwise we probe docker, podman, then nerdctl
+// (in that order, matching their relative ubiquity) and use the first one
+// whose CLI responds.
+func detectContainerRuntime() (containerRuntime, error) {
+	candidates := []containerRuntime{dockerRuntime{}, podmanRuntime{}, nerdctlRuntime{}}
+
+	if forced := strings.ToLower(strings.TrimSpace(os.Getenv("LF_CONTAINER_RUNTIME"))); forced != "" {
+		for _, rt := range candidates {
+			if strings.ToLower(rt.Binary()) == forced || strings.ToLower(rt.Name()) == forced {
+				if err := rt.Available(); err != nil {
+					return nil, fmt.Errorf("LF_CONTAINER_RUNTIME=%s requested but %w", forced, err)
+				}
+				return rt, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown LF_CONTAINER_RUNTIME %q (expected docker, podman, or nerdctl)", forced)
+	}
+
+	var lastErr error = errors.New("no container runtime found")
+	for _, rt := range candidates {
+		if err := rt.Available(); err != nil {
+			lastErr = err
+			continue
+		}
+		return rt, nil
+	}
+	return nil, fmt.Errorf("no container runtime available (tried docker, podman, nerdctl): %w", lastErr)
+}
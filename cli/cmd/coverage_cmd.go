@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"llamafarm-cli/internal/coverage"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverageIn           []string
+	coverageThreshold    float64
+	coverageDiff         string
+	coverageFailOnDecr   bool
+	coverageBaselinePath string
+	coverageOut          string
+	coverageTrim         string
+	coverageFormat       string
+)
+
+// devCmd is the parent for developer-facing tooling that doesn't belong
+// under projects/datasets (currently just coverage reporting).
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Developer tooling for working on the LlamaFarm CLI itself",
+	Long:  "Developer tooling for working on the LlamaFarm CLI itself, such as coverage reporting.",
+}
+
+// coverageCmd is the parent for the lf dev coverage subcommands, sharing
+// the --in/--threshold/--diff/--fail-on-decrease flags.
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Report Go test coverage from one or more coverprofiles",
+	Long: `Report Go test coverage from one or more coverprofiles produced by
+"go test -coverprofile". Pass --in multiple times to merge coverprofiles
+from different packages or test runs; overlapping line ranges are
+deduplicated by taking the max hit count.`,
+}
+
+// loadCoverageSummary merges the --in coverprofiles, applies --diff
+// filtering, and exits the process on error or --threshold/--fail-on-decrease
+// violation. Shared by all three coverage subcommands.
+func loadCoverageSummary() *coverage.Summary {
+	if len(coverageIn) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one --in coverprofile is required")
+		os.Exit(1)
+	}
+
+	summary, err := coverage.Merge(coverageIn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if coverageDiff != "" {
+		changed, err := coverage.ChangedFiles(coverageDiff)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		summary = summary.FilterFiles(changed)
+	}
+
+	if coverageFailOnDecr {
+		baselinePath := coverageBaselinePath
+		if baselinePath == "" {
+			baselinePath = "coverage-baseline.json"
+		}
+		baseline, err := coverage.LoadBaseline(baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if summary.DecreasedFrom(baseline) {
+			fmt.Fprintf(os.Stderr, "Error: coverage decreased from baseline %s (lines %.1f%% vs %.1f%%, stmts %.1f%% vs %.1f%%, funcs %.1f%% vs %.1f%%)\n",
+				baselinePath,
+				summary.Total.LinePct(), baseline.Total.LinePct(),
+				summary.Total.StmtPct(), baseline.Total.StmtPct(),
+				summary.Total.FuncPct(), baseline.Total.FuncPct())
+			os.Exit(1)
+		}
+	}
+
+	if coverageThreshold > 0 && !summary.MeetsThreshold(coverageThreshold) {
+		fmt.Fprintf(os.Stderr, "Error: coverage below threshold %.1f%% (lines %.1f%%, stmts %.1f%%, funcs %.1f%%)\n",
+			coverageThreshold, summary.Total.LinePct(), summary.Total.StmtPct(), summary.Total.FuncPct())
+		os.Exit(1)
+	}
+
+	return summary
+}
+
+// openCoverageOut opens --out, or returns os.Stdout if it wasn't set.
+func openCoverageOut() (*os.File, error) {
+	if coverageOut == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(coverageOut)
+}
+
+var coverageSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Print a per-file coverage summary",
+	Long:  `Print a per-file line/statement/function coverage summary as text, markdown, or json (--format).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		summary := loadCoverageSummary()
+
+		out, err := openCoverageOut()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if out != os.Stdout {
+			defer out.Close()
+		}
+
+		switch coverageFormat {
+		case "markdown":
+			coverage.WriteSummaryMarkdown(out, summary, coverageTrim)
+		case "json":
+			if err := coverage.WriteSummaryJSON(out, summary); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			coverage.WriteSummaryText(out, summary, coverageTrim)
+		}
+	},
+}
+
+var coverageLCOVCmd = &cobra.Command{
+	Use:   "lcov",
+	Short: "Convert coverage to LCOV tracefile format",
+	Long:  "Convert coverage to LCOV tracefile format, for tools that expect lcov.info (e.g. VS Code coverage gutters).",
+	Run: func(cmd *cobra.Command, args []string) {
+		summary := loadCoverageSummary()
+
+		out, err := openCoverageOut()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if out != os.Stdout {
+			defer out.Close()
+		}
+		coverage.WriteLCOV(out, summary)
+	},
+}
+
+var coverageCoberturaCmd = &cobra.Command{
+	Use:   "cobertura",
+	Short: "Convert coverage to Cobertura XML",
+	Long:  "Convert coverage to Cobertura XML, for Jenkins/GitLab coverage widgets.",
+	Run: func(cmd *cobra.Command, args []string) {
+		summary := loadCoverageSummary()
+
+		out, err := openCoverageOut()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if out != os.Stdout {
+			defer out.Close()
+		}
+		if err := coverage.WriteCobertura(out, summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	coverageCmd.PersistentFlags().StringArrayVar(&coverageIn, "in", nil, "Input Go coverprofile (repeatable to merge multiple profiles)")
+	coverageCmd.PersistentFlags().Float64Var(&coverageThreshold, "threshold", 0, "Exit non-zero if total line/statement/function coverage falls below this percentage")
+	coverageCmd.PersistentFlags().StringVar(&coverageDiff, "diff", "", "Restrict reporting to files changed vs. this git ref")
+	coverageCmd.PersistentFlags().BoolVar(&coverageFailOnDecr, "fail-on-decrease", false, "Exit non-zero if coverage decreased from the baseline JSON (see --baseline)")
+	coverageCmd.PersistentFlags().StringVar(&coverageBaselinePath, "baseline", "coverage-baseline.json", "Baseline summary JSON to compare against with --fail-on-decrease")
+	coverageCmd.PersistentFlags().StringVar(&coverageOut, "out", "", "Output file (default: stdout)")
+
+	coverageSummaryCmd.Flags().StringVar(&coverageFormat, "format", "text", "Summary format: text, markdown, or json")
+	coverageSummaryCmd.Flags().StringVar(&coverageTrim, "trim", "llamafarm/", "Trim this path prefix from the file column if present")
+
+	coverageCmd.AddCommand(coverageSummaryCmd)
+	coverageCmd.AddCommand(coverageLCOVCmd)
+	coverageCmd.AddCommand(coverageCoberturaCmd)
+
+	devCmd.AddCommand(coverageCmd)
+	rootCmd.AddCommand(devCmd)
+}
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"llamafarm-cli/cmd/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	createSet []string
+
+	createParserType string
+	createParserExt  string
+
+	createEmbedderType  string
+	createEmbedderModel string
+
+	createVectorstoreType string
+	createVectorstoreURL  string
+
+	createDatasetFiles []string
+
+	createPromptFromFile string
+
+	createRegister bool
+)
+
+// createCmd groups shorthand commands that scaffold a single RAG component
+// (parser, embedder, vector store, dataset, or prompt) into llamafarm.yaml,
+// so users don't have to hand-edit YAML for the common case of adding one
+// component at a time.
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Scaffold a RAG component into llamafarm.yaml",
+	Long: `Scaffold a single RAG component (parser, embedder, vector store,
+dataset, or prompt) into the current llamafarm.yaml.
+
+Available commands:
+  parser      - Add a named parser
+  embedder    - Add a named embedder
+  vectorstore - Add a named vector store
+  dataset     - Add a dataset from local files
+  prompt      - Add a named prompt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("LlamaFarm Create")
+		cmd.Help()
+	},
+}
+
+var createParserCmd = &cobra.Command{
+	Use:   "parser <name>",
+	Short: "Add a named parser to llamafarm.yaml",
+	Long: `Adds a named parser to the rag.parsers section of llamafarm.yaml.
+
+Example:
+  lf create parser my-pdf --type pdf --ext .pdf,.md --set priority=10`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		parser := config.ParserConfig{Type: createParserType}
+		if createParserExt != "" {
+			parser.FileExtensions = splitAndTrim(createParserExt)
+		}
+		if err := applySetFlags(&parser.Config, createSet); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfigForCreate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.AddParser(name, parser); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added parser '%s' (type: %s)\n", name, createParserType)
+
+		if createRegister {
+			registerConfig(cfg)
+		}
+	},
+}
+
+var createEmbedderCmd = &cobra.Command{
+	Use:   "embedder <name>",
+	Short: "Add a named embedder to llamafarm.yaml",
+	Long: `Adds a named embedder to the rag.embedders section of llamafarm.yaml.
+
+Example:
+  lf create embedder my-embedder --type ollama --model nomic-embed-text`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		embedder := config.EmbedderConfig{Type: createEmbedderType}
+		if createEmbedderModel != "" {
+			setDottedPath(&embedder.Config, "model", createEmbedderModel)
+		}
+		if err := applySetFlags(&embedder.Config, createSet); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfigForCreate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.AddEmbedder(name, embedder); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added embedder '%s' (type: %s)\n", name, createEmbedderType)
+
+		if createRegister {
+			registerConfig(cfg)
+		}
+	},
+}
+
+var createVectorstoreCmd = &cobra.Command{
+	Use:   "vectorstore <name>",
+	Short: "Add a named vector store to llamafarm.yaml",
+	Long: `Adds a named vector store to the rag.vector_stores section of llamafarm.yaml.
+
+Example:
+  lf create vectorstore my-store --type chroma --url http://localhost:8001`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		vectorStore := config.VectorStoreConfig{Type: createVectorstoreType}
+		if createVectorstoreURL != "" {
+			setDottedPath(&vectorStore.Config, "url", createVectorstoreURL)
+		}
+		if err := applySetFlags(&vectorStore.Config, createSet); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfigForCreate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.AddVectorStore(name, vectorStore); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added vector store '%s' (type: %s)\n", name, createVectorstoreType)
+
+		if createRegister {
+			registerConfig(cfg)
+		}
+	},
+}
+
+var createDatasetCmd = &cobra.Command{
+	Use:   "dataset <name>",
+	Short: "Add a dataset to llamafarm.yaml from local files",
+	Long: `Adds a dataset entry to llamafarm.yaml, resolving -f glob patterns
+against the local filesystem.
+
+Example:
+  lf create dataset my-docs -f ./docs/*.md -f ./docs/*.pdf`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		var files []string
+		for _, pattern := range createDatasetFiles {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid file pattern '%s': %v\n", pattern, err)
+				os.Exit(1)
+			}
+			files = append(files, matches...)
+		}
+
+		cfg, err := loadConfigForCreate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.AddDataset(config.Dataset{Name: name, Files: files}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added dataset '%s' (%d file(s))\n", name, len(files))
+
+		if createRegister {
+			registerDataset(name)
+		}
+	},
+}
+
+var createPromptCmd = &cobra.Command{
+	Use:   "prompt <name>",
+	Short: "Add a named prompt to llamafarm.yaml",
+	Long: `Adds a named prompt to llamafarm.yaml, reading its text from --from-file.
+
+Example:
+  lf create prompt greeting --from-file ./p.txt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if createPromptFromFile == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from-file is required")
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(createPromptFromFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file '%s': %v\n", createPromptFromFile, err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfigForCreate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.AddPrompt(config.Prompt{Name: name, Prompt: string(data)}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveConfig(cfg, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added prompt '%s'\n", name)
+
+		if createRegister {
+			registerConfig(cfg)
+		}
+	},
+}
+
+func init() {
+	createParserCmd.Flags().StringVar(&createParserType, "type", "", "Parser type (e.g. pdf, markdown)")
+	createParserCmd.Flags().StringVar(&createParserExt, "ext", "", "Comma-separated file extensions this parser handles (e.g. .pdf,.md)")
+	createParserCmd.Flags().StringArrayVar(&createSet, "set", nil, "Set a config value by dotted path, e.g. --set chunking.size=500 (repeatable)")
+	createParserCmd.Flags().BoolVar(&createRegister, "register", false, "Also push the updated config to the server")
+
+	createEmbedderCmd.Flags().StringVar(&createEmbedderType, "type", "", "Embedder type (e.g. ollama, openai)")
+	createEmbedderCmd.Flags().StringVar(&createEmbedderModel, "model", "", "Shorthand for --set model=<value>")
+	createEmbedderCmd.Flags().StringArrayVar(&createSet, "set", nil, "Set a config value by dotted path, e.g. --set dimensions=768 (repeatable)")
+	createEmbedderCmd.Flags().BoolVar(&createRegister, "register", false, "Also push the updated config to the server")
+
+	createVectorstoreCmd.Flags().StringVar(&createVectorstoreType, "type", "", "Vector store type (e.g. chroma, qdrant)")
+	createVectorstoreCmd.Flags().StringVar(&createVectorstoreURL, "url", "", "Shorthand for --set url=<value>")
+	createVectorstoreCmd.Flags().StringArrayVar(&createSet, "set", nil, "Set a config value by dotted path, e.g. --set collection.name=docs (repeatable)")
+	createVectorstoreCmd.Flags().BoolVar(&createRegister, "register", false, "Also push the updated config to the server")
+
+	createDatasetCmd.Flags().StringArrayVarP(&createDatasetFiles, "file", "f", nil, "Glob pattern of files to include (repeatable)")
+	createDatasetCmd.Flags().BoolVar(&createRegister, "register", false, "Also create this dataset on the server")
+
+	createPromptCmd.Flags().StringVar(&createPromptFromFile, "from-file", "", "Path to a file containing the prompt text")
+	createPromptCmd.Flags().BoolVar(&createRegister, "register", false, "Also push the updated config to the server")
+
+	createCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file path (default: llamafarm.yaml in current directory)")
+
+	createCmd.AddCommand(createParserCmd)
+	createCmd.AddCommand(createEmbedderCmd)
+	createCmd.AddCommand(createVectorstoreCmd)
+	createCmd.AddCommand(createDatasetCmd)
+	createCmd.AddCommand(createPromptCmd)
+	rootCmd.AddCommand(createCmd)
+}
+
+// loadConfigForCreate loads the current llamafarm.yaml, or starts a fresh
+// config if none exists yet, so 'lf create' also works as the first command
+// run in a new project directory.
+func loadConfigForCreate() (*config.LlamaFarmConfig, error) {
+	candidates := []string{configFile}
+	if configFile == "" {
+		candidates = []string{"llamafarm.yaml", "llamafarm.yml"}
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return config.LoadConfig(configFile)
+		}
+	}
+	return &config.LlamaFarmConfig{Version: "v1"}, nil
+}
+
+// splitAndTrim splits a comma-separated list and trims whitespace from each
+// element, dropping empty entries.
+func splitAndTrim(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// applySetFlags applies a batch of --set key=value flags (dotted paths) onto
+// *cfg, initializing it if needed.
+func applySetFlags(cfg *map[string]interface{}, sets []string) error {
+	for _, kv := range sets {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid --set value '%s', expected key=value", kv)
+		}
+		setDottedPath(cfg, strings.TrimSpace(parts[0]), parts[1])
+	}
+	return nil
+}
+
+// setDottedPath writes value into *cfg at the given dotted path (e.g.
+// "chunking.size"), creating intermediate maps as needed. value is parsed as
+// JSON when possible (so "true", "10", or "[1,2]" become their native types),
+// falling back to a plain string.
+func setDottedPath(cfg *map[string]interface{}, path string, value string) {
+	if *cfg == nil {
+		*cfg = map[string]interface{}{}
+	}
+	m := *cfg
+	parts := strings.Split(path, ".")
+	for i, key := range parts {
+		if i == len(parts)-1 {
+			m[key] = parseSetValue(value)
+			return
+		}
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+}
+
+// parseSetValue parses a --set value as JSON when possible (covering
+// numbers, booleans, arrays, and objects), falling back to the raw string.
+func parseSetValue(raw string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		return v
+	}
+	return raw
+}
+
+// registerConfig is a best-effort push of the updated config to the server's
+// project config endpoint. A failure here doesn't undo the local
+// llamafarm.yaml write: the component is already scaffolded locally, and the
+// user can retry registration (or apply it via a future deploy step)
+// without having to redo the local edit.
+func registerConfig(cfg *config.LlamaFarmConfig) {
+	serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	if err := ensureServerAvailable(serverCfg.URL); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+
+	payload, _ := json.Marshal(cfg)
+	url := buildServerURL(serverCfg.URL, fmt.Sprintf("/v1/projects/%s/%s/config", serverCfg.Namespace, serverCfg.Project))
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(payload))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Fprintf(os.Stderr, "Warning: server rejected config update (%d): %s\n", resp.StatusCode, prettyServerError(resp, body))
+		return
+	}
+	fmt.Println("Registered updated config with server")
+}
+
+// registerDataset is a best-effort creation of the named dataset on the
+// server, reusing the same endpoint and types as 'lf datasets add'.
+func registerDataset(name string) {
+	serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	if err := ensureServerAvailable(serverCfg.URL); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+
+	createReq := createDatasetRequest{Name: name, RAGStrategy: "auto"}
+	payload, _ := json.Marshal(createReq)
+	url := buildServerURL(serverCfg.URL, fmt.Sprintf("/v1/projects/%s/%s/datasets/", serverCfg.Namespace, serverCfg.Project))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not register with server: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Warning: server rejected dataset create (%d): %s\n", resp.StatusCode, prettyServerError(resp, body))
+		return
+	}
+	fmt.Println("Registered dataset with server")
+}
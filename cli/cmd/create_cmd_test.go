@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSetValue(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want interface{}
+	}{
+		{"string", "markdown", "markdown"},
+		{"int", "10", float64(10)},
+		{"bool", "true", true},
+		{"array", "[1,2]", []interface{}{float64(1), float64(2)}},
+		{"object", `{"a":1}`, map[string]interface{}{"a": float64(1)}},
+		{"unquoted_string_stays_string", "not-json", "not-json"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseSetValue(tc.raw)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("parseSetValue(%q) = %#v; want %#v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetDottedPath(t *testing.T) {
+	t.Run("top_level_key", func(t *testing.T) {
+		cfg := map[string]interface{}{}
+		setDottedPath(&cfg, "model", "nomic-embed-text")
+		if cfg["model"] != "nomic-embed-text" {
+			t.Fatalf("expected cfg[model] to be set, got %#v", cfg)
+		}
+	})
+
+	t.Run("nested_path_creates_intermediate_maps", func(t *testing.T) {
+		cfg := map[string]interface{}{}
+		setDottedPath(&cfg, "chunking.size", "500")
+		chunking, ok := cfg["chunking"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected cfg[chunking] to be a map, got %#v", cfg["chunking"])
+		}
+		if chunking["size"] != float64(500) {
+			t.Fatalf("expected chunking.size = 500, got %#v", chunking["size"])
+		}
+	})
+
+	t.Run("nil_map_is_initialized", func(t *testing.T) {
+		var cfg map[string]interface{}
+		setDottedPath(&cfg, "a.b", "c")
+		if cfg == nil {
+			t.Fatalf("expected setDottedPath to initialize a nil map")
+		}
+		a, ok := cfg["a"].(map[string]interface{})
+		if !ok || a["b"] != "c" {
+			t.Fatalf("expected cfg[a][b] = c, got %#v", cfg)
+		}
+	})
+
+	t.Run("existing_intermediate_value_is_overwritten", func(t *testing.T) {
+		cfg := map[string]interface{}{"a": "not-a-map"}
+		setDottedPath(&cfg, "a.b", "c")
+		a, ok := cfg["a"].(map[string]interface{})
+		if !ok || a["b"] != "c" {
+			t.Fatalf("expected a non-map intermediate value to be replaced with a map, got %#v", cfg)
+		}
+	})
+}
+
+func TestApplySetFlags(t *testing.T) {
+	t.Run("applies_multiple_sets", func(t *testing.T) {
+		cfg := map[string]interface{}{}
+		if err := applySetFlags(&cfg, []string{"chunking.size=500", "priority=10"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chunking, ok := cfg["chunking"].(map[string]interface{})
+		if !ok || chunking["size"] != float64(500) {
+			t.Fatalf("expected chunking.size = 500, got %#v", cfg)
+		}
+		if cfg["priority"] != float64(10) {
+			t.Fatalf("expected priority = 10, got %#v", cfg["priority"])
+		}
+	})
+
+	t.Run("rejects_missing_equals", func(t *testing.T) {
+		cfg := map[string]interface{}{}
+		if err := applySetFlags(&cfg, []string{"no-equals-sign"}); err == nil {
+			t.Fatalf("expected an error for a --set value with no '='")
+		}
+	})
+
+	t.Run("rejects_empty_key", func(t *testing.T) {
+		cfg := map[string]interface{}{}
+		if err := applySetFlags(&cfg, []string{"=value"}); err == nil {
+			t.Fatalf("expected an error for a --set value with an empty key")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// uploadSignalContext returns a context canceled when the process receives
+// its first shutdown signal, routed through installSignalCleanup's single
+// signal-handling path (see onShutdown) rather than registering an
+// independent signal.NotifyContext that could race the global handler to
+// exit first. The returned stop function both cancels the context and
+// unregisters the callback, so callers should still defer it as usual. A
+// second signal is handled entirely by installSignalCleanup itself (it
+// skips cleanup and exits immediately); this context is never canceled a
+// second time and has no hard-exit behavior of its own.
+func uploadSignalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	unregister := onShutdown(cancel)
+	return ctx, func() {
+		unregister()
+		cancel()
+	}
+}
+
+// cliError is a typed error for dataset operations that carries enough
+// structure for callers to print an actionable hint instead of a bare
+// "request failed" message, and for retry logic to distinguish transient
+// server/network failures from permanent ones.
+type cliError struct {
+	// Code is a short machine-stable identifier, e.g. "upload_failed".
+	Code string
+	// HTTPStatus is the server's response status, or 0 for a network error
+	// that never got a response.
+	HTTPStatus int
+	// Hint is a one-line, actionable suggestion shown alongside the error.
+	Hint string
+	// Err is the underlying error (a parsed server message, or a network
+	// error) this wraps.
+	Err error
+}
+
+func (e *cliError) Error() string {
+	if e.Hint == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s (%s)", e.Err.Error(), e.Hint)
+}
+
+func (e *cliError) Unwrap() error { return e.Err }
+
+// uploadHTTPError builds a cliError from a failed upload-related HTTP
+// response, attaching a hint for the status codes users hit in practice.
+func uploadHTTPError(resp *http.Response, body []byte) *cliError {
+	return &cliError{
+		Code:       "upload_failed",
+		HTTPStatus: resp.StatusCode,
+		Hint:       hintForStatus(resp.StatusCode),
+		Err:        fmt.Errorf("%s", prettyServerError(resp, body)),
+	}
+}
+
+// hintForStatus maps server status codes we know how to react to into an
+// actionable suggestion. It returns "" for codes with nothing more specific
+// to say than the server's own error message.
+func hintForStatus(status int) string {
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		return "file exceeds server max_upload_size, split with `lf datasets ingest --chunk-size`"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "check your server credentials/namespace, or re-run `lf login`"
+	case http.StatusTooManyRequests:
+		return "server is rate-limiting uploads, retry with a smaller --parallel"
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return "server may be restarting; retrying automatically"
+	default:
+		return ""
+	}
+}
+
+// isRetryableUploadError reports whether err is a transient failure worth
+// retrying: a 5xx cliError, or a network error that never reached the
+// server (a cliError with an HTTPStatus of 0, or any error that isn't a
+// cliError, means no response was received at all).
+func isRetryableUploadError(err error) bool {
+	if err == nil {
+		return false
+	}
+	// A 304 from upload-init means the server already has the blob: an
+	// outcome for the caller to act on, not a failure to retry.
+	if errors.Is(err, errUploadNotModified) {
+		return false
+	}
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return ce.HTTPStatus >= 500 || ce.HTTPStatus == 0
+	}
+	// Any other error (e.g. a raw network error from http.Client.Do) is
+	// treated as transient too.
+	return true
+}
+
+// uploadRetrySchedule is the exponential backoff between retry attempts:
+// 500ms, then 4s, capped at 3 total attempts.
+var uploadRetrySchedule = []time.Duration{500 * time.Millisecond, 4 * time.Second}
+
+const maxUploadAttempts = 3
+
+// retryUpload calls fn up to maxUploadAttempts times, backing off between
+// retryable failures (5xx responses and network errors) per
+// uploadRetrySchedule. It gives up immediately on non-retryable errors
+// (4xx, context cancellation) and on ctx.Done().
+func retryUpload(ctx context.Context, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt < maxUploadAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRetryableUploadError(err) || attempt == maxUploadAttempts-1 {
+			return err
+		}
+		backoff := uploadRetrySchedule[int(math.Min(float64(attempt), float64(len(uploadRetrySchedule)-1)))]
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
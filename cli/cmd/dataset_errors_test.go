@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHintForStatus(t *testing.T) {
+	if hint := hintForStatus(http.StatusRequestEntityTooLarge); hint == "" {
+		t.Fatalf("expected a hint for 413")
+	}
+	if hint := hintForStatus(http.StatusTeapot); hint != "" {
+		t.Fatalf("expected no hint for an unmapped status, got %q", hint)
+	}
+}
+
+func TestIsRetryableUploadError(t *testing.T) {
+	if isRetryableUploadError(nil) {
+		t.Fatalf("nil error should not be retryable")
+	}
+	if !isRetryableUploadError(errors.New("connection reset")) {
+		t.Fatalf("a raw network error should be retryable")
+	}
+	if !isRetryableUploadError(&cliError{HTTPStatus: 503, Err: errors.New("unavailable")}) {
+		t.Fatalf("a 5xx cliError should be retryable")
+	}
+	if isRetryableUploadError(&cliError{HTTPStatus: 413, Err: errors.New("too large")}) {
+		t.Fatalf("a 4xx cliError should not be retryable")
+	}
+}
+
+func TestRetryUpload_SucceedsAfterTransientFailures(t *testing.T) {
+	attempts := 0
+	err := retryUpload(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return &cliError{HTTPStatus: 503, Err: errors.New("unavailable")}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryUpload_GivesUpOnNonRetryable(t *testing.T) {
+	attempts := 0
+	err := retryUpload(context.Background(), func() error {
+		attempts++
+		return &cliError{HTTPStatus: 413, Err: errors.New("too large")}
+	})
+	if err == nil {
+		t.Fatalf("expected error to propagate")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected to give up after the first attempt, got %d", attempts)
+	}
+}
+
+func TestRetryUpload_StopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := retryUpload(ctx, func() error {
+		attempts++
+		return &cliError{HTTPStatus: 503, Err: errors.New("unavailable")}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if attempts != 0 {
+		t.Fatalf("expected no attempts once context is already canceled, got %d", attempts)
+	}
+}
+
+func TestRetryUpload_ExhaustsAttempts(t *testing.T) {
+	start := time.Now()
+	attempts := 0
+	err := retryUpload(context.Background(), func() error {
+		attempts++
+		return &cliError{HTTPStatus: 500, Err: errors.New("server error")}
+	})
+	if err == nil {
+		t.Fatalf("expected error after exhausting attempts")
+	}
+	if attempts != maxUploadAttempts {
+		t.Fatalf("expected %d attempts, got %d", maxUploadAttempts, attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Fatalf("expected backoff sleeps between attempts, only took %s", elapsed)
+	}
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"llamafarm-cli/cmd/config"
+	"llamafarm-cli/internal/datasetcache"
+)
+
+// datasetRef is a parsed "name@version" dataset reference, e.g.
+// "acme/legal-docs@v3". Version defaults to "latest" when omitted.
+type datasetRef struct {
+	Name    string
+	Version string
+}
+
+func parseDatasetRef(s string) datasetRef {
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		return datasetRef{Name: s[:i], Version: s[i+1:]}
+	}
+	return datasetRef{Name: s, Version: "latest"}
+}
+
+func (r datasetRef) String() string { return fmt.Sprintf("%s@%s", r.Name, r.Version) }
+
+// datasetManifestFile is one file recorded in a dataset package's manifest.
+type datasetManifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// datasetManifest describes a packaged dataset artifact, whether read back
+// from the local cache or produced by `lf datasets push`.
+type datasetManifest struct {
+	Name        string                `json:"name"`
+	RAGStrategy string                `json:"rag_strategy,omitempty"`
+	Files       []datasetManifestFile `json:"files"`
+	Digest      string                `json:"digest,omitempty"`
+}
+
+// datasetCacheDir returns ~/.llamafarm/datasets/<name>/<version>, the local
+// cache Helm-style resolution checks before falling back to a registry.
+func datasetCacheDir(ref datasetRef) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".llamafarm", "datasets", ref.Name, ref.Version), nil
+}
+
+func readCachedManifest(dir string) (*datasetManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	var m datasetManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing cached manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// fetchFromRegistry resolves ref against reg and extracts the resulting
+// package into cacheDir, returning its manifest.
+func fetchFromRegistry(reg config.DatasetRegistry, ref datasetRef, cacheDir string) (*datasetManifest, error) {
+	if reg.Type == "oci" {
+		return fetchOCI(reg, ref, cacheDir)
+	}
+	return fetchHTTP(reg, ref, cacheDir)
+}
+
+// fetchHTTP downloads {registry}/datasets/{name}/{version}.tar.gz.
+func fetchHTTP(reg config.DatasetRegistry, ref datasetRef, cacheDir string) (*datasetManifest, error) {
+	url := buildServerURL(reg.URL, fmt.Sprintf("/datasets/%s/%s.tar.gz", ref.Name, ref.Version))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("registry %q: %s", reg.Name, prettyServerError(resp, body))
+	}
+	return extractDatasetArchive(resp.Body, cacheDir)
+}
+
+// fetchOCI pulls ref from reg via a minimal subset of the OCI distribution
+// spec: fetch the manifest, then the first layer blob, which is expected to
+// be the same tar.gz package fetchHTTP would return.
+func fetchOCI(reg config.DatasetRegistry, ref datasetRef, cacheDir string) (*datasetManifest, error) {
+	manifestURL := buildServerURL(reg.URL, fmt.Sprintf("/v2/%s/manifests/%s", ref.Name, ref.Version))
+	req, err := http.NewRequest(http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.oci.image.manifest.v1+json")
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if readErr != nil {
+			return nil, readErr
+		}
+		return nil, fmt.Errorf("registry %q: %s", reg.Name, prettyServerError(resp, body))
+	}
+
+	var ociManifest struct {
+		Layers []struct {
+			Digest string `json:"digest"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(body, &ociManifest); err != nil {
+		return nil, fmt.Errorf("parsing OCI manifest from %q: %w", reg.Name, err)
+	}
+	if len(ociManifest.Layers) == 0 {
+		return nil, fmt.Errorf("registry %q: manifest for %s has no layers", reg.Name, ref)
+	}
+
+	blobURL := buildServerURL(reg.URL, fmt.Sprintf("/v2/%s/blobs/%s", ref.Name, ociManifest.Layers[0].Digest))
+	blobReq, err := http.NewRequest(http.MethodGet, blobURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	blobResp, err := getHTTPClient().Do(blobReq)
+	if err != nil {
+		return nil, err
+	}
+	defer blobResp.Body.Close()
+	if blobResp.StatusCode != http.StatusOK {
+		blobBody, _ := io.ReadAll(blobResp.Body)
+		return nil, fmt.Errorf("registry %q: %s", reg.Name, prettyServerError(blobResp, blobBody))
+	}
+	return extractDatasetArchive(blobResp.Body, cacheDir)
+}
+
+// extractDatasetArchive unpacks a tar.gz dataset package (manifest.json plus
+// data files) into dir and returns the manifest.
+func extractDatasetArchive(r io.Reader, dir string) (*datasetManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading package: %w", err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading package: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dest := filepath.Join(dir, filepath.Clean(string(filepath.Separator)+hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, err
+		}
+		out, err := os.Create(dest)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return nil, err
+		}
+		out.Close()
+	}
+	return readCachedManifest(dir)
+}
+
+// verifyDatasetSignature checks a detached ed25519 signature
+// (manifest.json.sig, hex-encoded) in dir against a hex-encoded public key
+// file, the way `helm --verify` checks a provenance file against a keyring.
+// A no-op when keyring is empty.
+func verifyDatasetSignature(dir, keyring string) error {
+	if keyring == "" {
+		return nil
+	}
+	sigHex, err := os.ReadFile(filepath.Join(dir, "manifest.json.sig"))
+	if err != nil {
+		return fmt.Errorf("reading detached signature: %w", err)
+	}
+	sig, err := hex.DecodeString(strings.TrimSpace(string(sigHex)))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	keyHex, err := os.ReadFile(keyring)
+	if err != nil {
+		return fmt.Errorf("reading keyring %s: %w", keyring, err)
+	}
+	pub, err := hex.DecodeString(strings.TrimSpace(string(keyHex)))
+	if err != nil {
+		return fmt.Errorf("decoding keyring %s: %w", keyring, err)
+	}
+	manifestData, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), manifestData, sig) {
+		return fmt.Errorf("signature verification failed against keyring %s", keyring)
+	}
+	return nil
+}
+
+// packageDataset walks dir, hashing every file into a manifest, and packs
+// manifest.json plus the files themselves into a tar.gz written to a temp
+// file. The caller is responsible for removing the returned path.
+func packageDataset(name, ragStrategy, dir string) (*datasetManifest, string, error) {
+	var files []datasetManifestFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sha, size, err := datasetcache.HashFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, datasetManifestFile{Path: filepath.ToSlash(rel), SHA256: sha, Size: size})
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("walking %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no files found under %s", dir)
+	}
+
+	manifest := &datasetManifest{Name: name, RAGStrategy: ragStrategy, Files: files}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+
+	archive, err := os.CreateTemp("", "llamafarm-dataset-*.tar.gz")
+	if err != nil {
+		return nil, "", err
+	}
+	defer archive.Close()
+
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	if err := writeTarEntry(tw, "manifest.json", manifestData); err != nil {
+		return nil, "", err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f.Path))
+		if err != nil {
+			return nil, "", err
+		}
+		if err := writeTarEntry(tw, f.Path, data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", err
+	}
+
+	digest, _, err := datasetcache.HashFile(archive.Name())
+	if err != nil {
+		return nil, "", err
+	}
+	manifest.Digest = digest
+	return manifest, archive.Name(), nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// publishDatasetPackage streams archivePath to the server as a multipart
+// upload (io.Pipe + multipart.Writer running in a goroutine, so the whole
+// tarball is never buffered twice) and returns the digest the server
+// recorded for it.
+func publishDatasetPackage(server, namespace, project, dataset, archivePath string) (string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("package", filepath.Base(archivePath))
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+
+	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/packages", namespace, project, dataset))
+	req, err := http.NewRequest(http.MethodPost, url, pr)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, readErr := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		if readErr != nil {
+			return "", readErr
+		}
+		return "", fmt.Errorf("%s", prettyServerError(resp, body))
+	}
+
+	var out struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("parsing push response: %w", err)
+	}
+	return out.Digest, nil
+}
+
+// publishedDatasetsPath returns ~/.llamafarm/datasets/published.json, which
+// maps "namespace/project/dataset" to the digest of its last successful
+// push, so `lf datasets list` can show a version without another server
+// round-trip.
+func publishedDatasetsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".llamafarm", "datasets", "published.json"), nil
+}
+
+func publishedDatasetKey(namespace, project, dataset string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, project, dataset)
+}
+
+func recordDatasetDigest(namespace, project, dataset, digest string) error {
+	path, err := publishedDatasetsPath()
+	if err != nil {
+		return err
+	}
+	state := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	state[publishedDatasetKey(namespace, project, dataset)] = digest
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// lookupDatasetDigest returns the last digest recorded for a dataset by
+// `lf datasets push`, or "" if none is known locally.
+func lookupDatasetDigest(namespace, project, dataset string) string {
+	path, err := publishedDatasetsPath()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	state := map[string]string{}
+	if json.Unmarshal(data, &state) != nil {
+		return ""
+	}
+	return state[publishedDatasetKey(namespace, project, dataset)]
+}
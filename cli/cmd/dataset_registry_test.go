@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"llamafarm-cli/cmd/config"
+)
+
+func TestParseDatasetRef(t *testing.T) {
+	if r := parseDatasetRef("acme/legal-docs@v3"); r.Name != "acme/legal-docs" || r.Version != "v3" {
+		t.Fatalf("unexpected ref: %+v", r)
+	}
+	if r := parseDatasetRef("acme/legal-docs"); r.Name != "acme/legal-docs" || r.Version != "latest" {
+		t.Fatalf("expected default version 'latest', got %+v", r)
+	}
+}
+
+func buildTestPackage(t *testing.T, manifest []byte, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := writeTarEntry(tw, "manifest.json", manifest); err != nil {
+		t.Fatalf("writing manifest entry: %v", err)
+	}
+	for name, data := range files {
+		if err := writeTarEntry(tw, name, data); err != nil {
+			t.Fatalf("writing %s entry: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("closing tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("closing gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchFromRegistry_HTTP(t *testing.T) {
+	manifest := []byte(`{"name":"acme/docs","files":[{"path":"a.txt","sha256":"x","size":1}]}`)
+	pkg := buildTestPackage(t, manifest, map[string][]byte{"a.txt": []byte("a")})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/datasets/acme/docs/v1.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(pkg)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	reg := config.DatasetRegistry{Name: "origin", URL: ts.URL, Type: "http"}
+	m, err := fetchFromRegistry(reg, datasetRef{Name: "acme/docs", Version: "v1"}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name != "acme/docs" || len(m.Files) != 1 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a.txt")); err != nil || string(data) != "a" {
+		t.Fatalf("expected extracted file content 'a', got %q (err %v)", data, err)
+	}
+}
+
+func TestVerifyDatasetSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	dir := t.TempDir()
+	manifestData := []byte(`{"name":"acme/docs"}`)
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), manifestData, 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+	sig := ed25519.Sign(priv, manifestData)
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json.sig"), []byte(hex.EncodeToString(sig)), 0o644); err != nil {
+		t.Fatalf("writing signature: %v", err)
+	}
+	keyring := filepath.Join(dir, "keyring.hex")
+	if err := os.WriteFile(keyring, []byte(hex.EncodeToString(pub)), 0o644); err != nil {
+		t.Fatalf("writing keyring: %v", err)
+	}
+
+	if err := verifyDatasetSignature(dir, keyring); err != nil {
+		t.Fatalf("expected valid signature to verify, got %v", err)
+	}
+
+	// Tamper with the manifest; verification should now fail.
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"name":"tampered"}`), 0o644); err != nil {
+		t.Fatalf("tampering manifest: %v", err)
+	}
+	if err := verifyDatasetSignature(dir, keyring); err == nil {
+		t.Fatalf("expected tampered manifest to fail verification")
+	}
+
+	if err := verifyDatasetSignature(dir, ""); err != nil {
+		t.Fatalf("expected no-op when keyring is empty, got %v", err)
+	}
+}
+
+func TestPackageDatasetAndRecordDigest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "doc.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	manifest, archivePath, err := packageDataset("acme/docs", "auto", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(archivePath)
+	if len(manifest.Files) != 1 || manifest.Digest == "" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	if err := recordDatasetDigest("ns", "proj", "acme-docs", manifest.Digest); err != nil {
+		t.Fatalf("recording digest: %v", err)
+	}
+	if got := lookupDatasetDigest("ns", "proj", "acme-docs"); got != manifest.Digest {
+		t.Fatalf("expected lookup to return recorded digest %q, got %q", manifest.Digest, got)
+	}
+	if got := lookupDatasetDigest("ns", "proj", "unknown"); got != "" {
+		t.Fatalf("expected empty digest for unrecorded dataset, got %q", got)
+	}
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"llamafarm-cli/internal/datasetcache"
+)
+
+// errUploadNotModified is returned by initResumableUpload when the server
+// already has a blob matching the If-None-Match digest, so there's nothing
+// left to upload.
+var errUploadNotModified = errors.New("blob already ingested")
+
+// uploadChunkSize is the size of each resumable chunk PATCHed to the server.
+// Files are never buffered whole in memory; only one chunk at a time.
+const uploadChunkSize = 8 * 1024 * 1024 // 8 MiB
+
+// uploadRecord is one entry of ~/.llamafarm/uploads.json, keyed by the
+// file's sha256 so a renamed-but-identical file still resumes correctly.
+type uploadRecord struct {
+	SHA256   string `json:"sha256"`
+	UploadID string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+}
+
+// uploadStatePath returns ~/.llamafarm/uploads.json.
+func uploadStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".llamafarm", "uploads.json"), nil
+}
+
+func loadUploadState() (map[string]uploadRecord, error) {
+	path, err := uploadStatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]uploadRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	state := map[string]uploadRecord{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return state, nil
+}
+
+func saveUploadState(state map[string]uploadRecord) error {
+	path, err := uploadStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+type initUploadRequest struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type initUploadResponse struct {
+	UploadID string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+}
+
+// initResumableUpload asks the server for an upload ID for a new upload, or
+// (if the server recognizes the sha256 from a prior, incomplete upload) the
+// offset to resume from. It sends sha as an If-None-Match precondition so a
+// server that already has this exact blob ingested (e.g. from another
+// dataset) can short-circuit with 304 Not Modified instead of re-accepting
+// the bytes; callers should treat errUploadNotModified as success.
+func initResumableUpload(ctx context.Context, server, namespace, project, dataset, sha string, size int64) (initUploadResponse, error) {
+	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/data/uploads", namespace, project, dataset))
+	payload, err := json.Marshal(initUploadRequest{SHA256: sha, Size: size})
+	if err != nil {
+		return initUploadResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return initUploadResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-None-Match", datasetcache.ETag(sha))
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return initUploadResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return initUploadResponse{}, errUploadNotModified
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		if readErr != nil {
+			return initUploadResponse{}, readErr
+		}
+		return initUploadResponse{}, uploadHTTPError(resp, body)
+	}
+	var out initUploadResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return initUploadResponse{}, fmt.Errorf("parsing upload-init response: %w", err)
+	}
+	return out, nil
+}
+
+// patchUploadChunk PATCHes one chunk at offset, tus-style, and returns the
+// new offset, offset+len(chunk), once the server accepts the chunk with 200
+// or 204. An offset reported by the server is not read.
+func patchUploadChunk(ctx context.Context, server, namespace, project, dataset, uploadID string, offset int64, chunk []byte, bar *uploadBar) (int64, error) {
+	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/data/uploads/%s", namespace, project, dataset, uploadID))
+	var body io.Reader = bytes.NewReader(chunk)
+	if bar != nil {
+		body = io.TeeReader(body, bar.teeWriter())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, body)
+	if err != nil {
+		return offset, err
+	}
+	req.ContentLength = int64(len(chunk))
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return offset, err
+	}
+	defer resp.Body.Close()
+	respBody, readErr := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		if readErr != nil {
+			return offset, readErr
+		}
+		return offset, uploadHTTPError(resp, respBody)
+	}
+	return offset + int64(len(chunk)), nil
+}
+
+// abortUpload tells the server to discard an in-flight upload, freeing
+// whatever partial blob it's holding. It's best-effort: called when a
+// signal interrupts uploadFileToDataset, so failures are only logged by the
+// caller, never returned as the operation's own error.
+func abortUpload(server, namespace, project, dataset, uploadID string) error {
+	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/data/uploads/%s", namespace, project, dataset, uploadID))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("abort upload %s responded %d", uploadID, resp.StatusCode)
+	}
+	return nil
+}
+
+// uploadFileToDataset uploads path in uploadChunkSize chunks, resuming from
+// ~/.llamafarm/uploads.json if a prior run was interrupted partway through.
+// bar may be nil if the caller doesn't want progress reported. ctx is
+// checked between chunks so a Ctrl-C stops after the in-flight PATCH
+// finishes instead of leaving it dangling; on cancellation, the server is
+// told to abort the upload so it can free the partial blob.
+func uploadFileToDataset(ctx context.Context, server, namespace, project, dataset, path string, bar *uploadBar) error {
+	sha, _, size, err := datasetcache.Put(path)
+	if err != nil {
+		return fmt.Errorf("hashing %s: %w", path, err)
+	}
+	bar.setTotal(size)
+
+	state, err := loadUploadState()
+	if err != nil {
+		return err
+	}
+	rec, resuming := state[sha]
+	if !resuming || rec.UploadID == "" {
+		var init initUploadResponse
+		err := retryUpload(ctx, func() error {
+			var err error
+			init, err = initResumableUpload(ctx, server, namespace, project, dataset, sha, size)
+			return err
+		})
+		if errors.Is(err, errUploadNotModified) {
+			bar.advance(size)
+			delete(state, sha)
+			return saveUploadState(state)
+		}
+		if err != nil {
+			return err
+		}
+		rec = uploadRecord{SHA256: sha, UploadID: init.UploadID, Offset: init.Offset}
+		state[sha] = rec
+		if err := saveUploadState(state); err != nil {
+			return err
+		}
+	}
+	bar.advance(rec.Offset)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if rec.Offset > 0 {
+		if _, err := f.Seek(rec.Offset, io.SeekStart); err != nil {
+			return fmt.Errorf("seeking %s to resume offset %d: %w", path, rec.Offset, err)
+		}
+	}
+
+	buf := make([]byte, uploadChunkSize)
+	offset := rec.Offset
+	for offset < size {
+		if ctx.Err() != nil {
+			if err := abortUpload(server, namespace, project, dataset, rec.UploadID); err != nil {
+				fmt.Fprintf(os.Stderr, "   Warning: failed to abort upload for %s: %v\n", path, err)
+			}
+			return ctx.Err()
+		}
+		n, err := io.ReadFull(f, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		chunk := buf[:n]
+		var newOffset int64
+		err = retryUpload(ctx, func() error {
+			var err error
+			newOffset, err = patchUploadChunk(ctx, server, namespace, project, dataset, rec.UploadID, offset, chunk, bar)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				if abortErr := abortUpload(server, namespace, project, dataset, rec.UploadID); abortErr != nil {
+					fmt.Fprintf(os.Stderr, "   Warning: failed to abort upload for %s: %v\n", path, abortErr)
+				}
+			}
+			return err
+		}
+		offset = newOffset
+		rec.Offset = offset
+		state[sha] = rec
+		if err := saveUploadState(state); err != nil {
+			return err
+		}
+	}
+
+	delete(state, sha)
+	return saveUploadState(state)
+}
+
+// uploadFilesConcurrent uploads files to dataset using up to parallel
+// workers, reporting per-file byte progress unless showProgress is false.
+// It returns the number of files that uploaded successfully. ctx is
+// typically the one from uploadSignalContext: once canceled, workers that
+// haven't started yet are skipped and in-flight ones wind down via
+// uploadFileToDataset's own cancellation handling.
+func uploadFilesConcurrent(ctx context.Context, server, namespace, project, dataset string, files []string, parallel int, showProgress, silent bool) int {
+	if parallel < 1 {
+		parallel = 1
+	}
+	renderer := newUploadProgressRenderer(showProgress && !silent)
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, parallel)
+		mu       sync.Mutex
+		uploaded int
+	)
+	for _, f := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		f := f
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			bar := renderer.barFor(filepath.Base(f), 0)
+			err := uploadFileToDataset(ctx, server, namespace, project, dataset, f, bar)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if !silent {
+					fmt.Fprintf(os.Stderr, "   ⚠️  Failed to upload '%s': %v\n", f, err)
+				}
+				return
+			}
+			if !silent {
+				fmt.Printf("   📤 Uploaded: %s\n", f)
+			}
+			uploaded++
+		}()
+	}
+	wg.Wait()
+	renderer.finish()
+	return uploaded
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"llamafarm-cli/internal/datasetcache"
+)
+
+// fakeUploadServer implements just enough of the tus-style init+PATCH
+// protocol for uploadFileToDataset to drive against.
+type fakeUploadServer struct {
+	mu       sync.Mutex
+	received map[string][]byte
+}
+
+func newFakeUploadServer() *httptest.Server {
+	f := &fakeUploadServer{received: map[string][]byte{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/ns/proj/datasets/ds/data/uploads", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"upload_id":"up-1","offset":0}`))
+	})
+	mux.HandleFunc("/v1/projects/ns/proj/datasets/ds/data/uploads/up-1", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		existing := f.received["up-1"]
+		if int64(len(existing)) < offset {
+			existing = append(existing, make([]byte, offset-int64(len(existing)))...)
+		}
+		f.received["up-1"] = append(existing[:offset], body...)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestUploadFileToDataset_ChunkedAndResumable(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	ts := newFakeUploadServer()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	content := make([]byte, uploadChunkSize*2+10) // spans 3 chunks
+	for i := range content {
+		content[i] = byte(i % 256)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	if err := uploadFileToDataset(context.Background(), ts.URL, "ns", "proj", "ds", path, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := loadUploadState()
+	if err != nil {
+		t.Fatalf("loading upload state: %v", err)
+	}
+	if len(state) != 0 {
+		t.Fatalf("expected upload state to be cleared on success, got %v", state)
+	}
+}
+
+func TestUploadFileToDataset_ResumesFromSavedOffset(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	ts := newFakeUploadServer()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	content := []byte("hello resumable world")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+	sha, _, err := datasetcache.HashFile(path)
+	if err != nil {
+		t.Fatalf("hashing file: %v", err)
+	}
+
+	// Pre-seed state as if a prior run already uploaded everything but the
+	// final byte, then got interrupted before deleting its record.
+	state := map[string]uploadRecord{sha: {SHA256: sha, UploadID: "up-1", Offset: int64(len(content) - 1)}}
+	if err := saveUploadState(state); err != nil {
+		t.Fatalf("seeding upload state: %v", err)
+	}
+
+	if err := uploadFileToDataset(context.Background(), ts.URL, "ns", "proj", "ds", path, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := loadUploadState()
+	if err != nil {
+		t.Fatalf("loading upload state: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected upload state to be cleared after resuming to completion, got %v", got)
+	}
+}
+
+func TestUploadFileToDataset_AbortsOnCancellation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var aborted atomic.Bool
+	firstChunkSent := make(chan struct{})
+	var closeOnce sync.Once
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/ns/proj/datasets/ds/data/uploads", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"upload_id":"up-1","offset":0}`))
+	})
+	mux.HandleFunc("/v1/projects/ns/proj/datasets/ds/data/uploads/up-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			aborted.Store(true)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		closeOnce.Do(func() { close(firstChunkSent) })
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	content := make([]byte, uploadChunkSize*2+10) // spans multiple chunks
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-firstChunkSent
+		cancel()
+	}()
+	if err := uploadFileToDataset(ctx, ts.URL, "ns", "proj", "ds", path, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !aborted.Load() {
+		t.Fatalf("expected the upload to be aborted server-side on cancellation")
+	}
+}
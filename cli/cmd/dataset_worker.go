@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"llamafarm-cli/internal/runtime"
+)
+
+const (
+	datasetWorkerImage         = "ghcr.io/llama-farm/llamafarm/ingest-worker:latest"
+	datasetWorkerContainerName = "llamafarm-ingest-worker"
+	datasetWorkerPort          = 8090
+)
+
+// startLocalIngestWorker starts (or reuses) the ingest-worker sidecar
+// container bound to stagingDir, giving 'lf datasets ingest --local-worker'
+// a way to ingest files without a running LlamaFarm server, e.g. for
+// air-gapped users. It returns the worker's base URL.
+func startLocalIngestWorker(ctx context.Context, stagingDir string) (string, error) {
+	client := runtime.NewClient("")
+	if err := client.Ping(ctx); err != nil {
+		return "", fmt.Errorf("docker engine socket unreachable: %w", err)
+	}
+	baseURL := fmt.Sprintf("http://localhost:%d", datasetWorkerPort)
+
+	running, err := client.IsRunning(ctx, datasetWorkerContainerName)
+	if err != nil {
+		return "", err
+	}
+	if running {
+		return baseURL, nil
+	}
+
+	exists, err := client.ContainerExists(ctx, datasetWorkerContainerName)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		if err := client.Start(ctx, datasetWorkerContainerName); err != nil {
+			return "", err
+		}
+		return baseURL, nil
+	}
+
+	fmt.Fprintln(os.Stderr, "Pulling ingest worker image...")
+	if events, err := client.Pull(ctx, datasetWorkerImage); err == nil {
+		for range events {
+			// Drain; 'lf datasets pull' already owns the rendered progress UI
+			// and this is a background sidecar, so we just wait for it to
+			// finish rather than duplicating a progress bar here.
+		}
+	}
+
+	opts := runtime.RunOptions{
+		Name:   datasetWorkerContainerName,
+		Image:  datasetWorkerImage,
+		Ports:  map[string]string{"8090/tcp": fmt.Sprintf("%d", datasetWorkerPort)},
+		Binds:  []string{fmt.Sprintf("%s:/staging", stagingDir)},
+		Labels: managedContainerLabels,
+	}
+	if _, err := client.Run(ctx, opts); err != nil {
+		return "", fmt.Errorf("failed to start ingest worker: %w", err)
+	}
+	return baseURL, nil
+}
+
+// waitHealthy polls baseURL's /healthz endpoint until it responds 200 or
+// timeout elapses.
+func waitHealthy(baseURL string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := http.Get(baseURL + "/healthz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("ingest worker did not become healthy within %s", timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// workerIngestRequest tells the worker which staged files (relative to its
+// /staging mount) to ingest into which dataset.
+type workerIngestRequest struct {
+	Dataset string   `json:"dataset"`
+	Files   []string `json:"files"`
+}
+
+// triggerWorkerIngest kicks off ingestion of the staged files, returning
+// once the worker has accepted the batch (ingestion itself is asynchronous;
+// callers poll pollIngestProgress for completion).
+func triggerWorkerIngest(baseURL, dataset string, relFiles []string) error {
+	payload, err := json.Marshal(workerIngestRequest{Dataset: dataset, Files: relFiles})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(baseURL+"/ingest", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ingest worker rejected batch (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// workerProgress mirrors the worker's GET /progress response.
+type workerProgress struct {
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Done      bool   `json:"done"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pollIngestProgress polls baseURL's /progress endpoint until the worker
+// reports the batch done, printing percent-complete updates unless silent.
+func pollIngestProgress(baseURL string, silent bool) error {
+	for {
+		resp, err := http.Get(baseURL + "/progress")
+		if err != nil {
+			return fmt.Errorf("polling ingest worker progress: %w", err)
+		}
+		var p workerProgress
+		err = json.NewDecoder(resp.Body).Decode(&p)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("parsing ingest worker progress: %w", err)
+		}
+		if p.Error != "" {
+			return fmt.Errorf("ingest worker reported error: %s", p.Error)
+		}
+		if !silent && p.Total > 0 {
+			fmt.Printf("\r   Ingesting... %d/%d", p.Completed, p.Total)
+		}
+		if p.Done {
+			if !silent && p.Total > 0 {
+				fmt.Println()
+			}
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// stageFilesForWorker copies files into a fresh temp directory for the
+// worker container to bind-mount at /staging. The caller is responsible for
+// removing the returned directory.
+func stageFilesForWorker(files []string) (string, error) {
+	dir, err := os.MkdirTemp("", "llamafarm-ingest-staging-*")
+	if err != nil {
+		return "", err
+	}
+	for i, f := range files {
+		dest := filepath.Join(dir, stagedFileName(i, f))
+		if err := copyFileForStaging(f, dest); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("staging %s: %w", f, err)
+		}
+	}
+	return dir, nil
+}
+
+// stagedFileName derives the name a source file is staged under, given its
+// position in the ingest file list. `lf datasets ingest` accepts multiple
+// paths/globs that can span different directories, so two inputs can share a
+// basename (e.g. a/report.pdf and b/report.pdf); index-prefixing keeps every
+// staged file distinct instead of one silently clobbering the other.
+// Callers that list staged files back to the worker (see ingestViaLocalWorker)
+// must derive the same name from the same index.
+func stagedFileName(index int, f string) string {
+	return fmt.Sprintf("%04d_%s", index, filepath.Base(f))
+}
+
+func copyFileForStaging(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	var ready atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	go func() {
+		time.Sleep(600 * time.Millisecond)
+		ready.Store(true)
+	}()
+
+	if err := waitHealthy(ts.URL, 5*time.Second); err != nil {
+		t.Fatalf("expected healthy within timeout, got %v", err)
+	}
+}
+
+func TestWaitHealthy_TimesOut(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	if err := waitHealthy(ts.URL, 200*time.Millisecond); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+}
+
+func TestTriggerWorkerIngestAndPollProgress(t *testing.T) {
+	var completed int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ingest", func(w http.ResponseWriter, r *http.Request) {
+		var req workerIngestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding ingest request: %v", err)
+		}
+		completed = int32(len(req.Files))
+		w.WriteHeader(http.StatusAccepted)
+	})
+	calls := 0
+	mux.HandleFunc("/progress", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		done := calls >= 2
+		json.NewEncoder(w).Encode(workerProgress{Total: int(completed), Completed: int(completed), Done: done})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	if err := triggerWorkerIngest(ts.URL, "ds", []string{"a.txt", "b.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pollIngestProgress(ts.URL, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls < 2 {
+		t.Fatalf("expected at least 2 polls, got %d", calls)
+	}
+}
+
+func TestPollIngestProgress_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(workerProgress{Error: "disk full"})
+	}))
+	defer ts.Close()
+
+	if err := pollIngestProgress(ts.URL, true); err == nil {
+		t.Fatalf("expected error to propagate")
+	}
+}
+
+func TestStageFilesForWorker(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	staged, err := stageFilesForWorker([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(staged)
+
+	data, err := os.ReadFile(filepath.Join(staged, stagedFileName(0, path)))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("expected staged file content 'hello', got %q (err %v)", data, err)
+	}
+}
+
+func TestStageFilesForWorker_CollidingBasenames(t *testing.T) {
+	dirA := filepath.Join(t.TempDir(), "a")
+	dirB := filepath.Join(t.TempDir(), "b")
+	if err := os.MkdirAll(dirA, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.MkdirAll(dirB, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	pathA := filepath.Join(dirA, "report.pdf")
+	pathB := filepath.Join(dirB, "report.pdf")
+	if err := os.WriteFile(pathA, []byte("from-a"), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+	if err := os.WriteFile(pathB, []byte("from-b"), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	staged, err := stageFilesForWorker([]string{pathA, pathB})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(staged)
+
+	dataA, err := os.ReadFile(filepath.Join(staged, stagedFileName(0, pathA)))
+	if err != nil || string(dataA) != "from-a" {
+		t.Fatalf("expected staged content 'from-a', got %q (err %v)", dataA, err)
+	}
+	dataB, err := os.ReadFile(filepath.Join(staged, stagedFileName(1, pathB)))
+	if err != nil || string(dataB) != "from-b" {
+		t.Fatalf("expected staged content 'from-b', got %q (err %v)", dataB, err)
+	}
+}
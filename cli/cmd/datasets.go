@@ -2,17 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"llamafarm-cli/cmd/config"
+	"llamafarm-cli/internal/datasetcache"
+	"llamafarm-cli/internal/errs"
 
 	"github.com/spf13/cobra"
 )
@@ -20,6 +23,13 @@ import (
 var (
 	configFile  string
 	ragStrategy string
+
+	uploadNoProgress  bool
+	uploadSilent      bool
+	uploadParallel    int
+	ingestLocalWorker bool
+
+	pullKeyring string
 )
 
 // datasetsCmd represents the datasets command
@@ -33,7 +43,10 @@ Available commands:
   list    - List all datasets on the server for a project
   add     - Create a dataset on the server (optionally then upload files)
   remove  - Delete a dataset from the server
-  ingest  - Upload files to a dataset on the server`,
+  ingest  - Upload files to a dataset on the server
+  pull    - Fetch a versioned dataset artifact into the local cache
+  push    - Package a local directory and publish it as a dataset artifact
+  verify  - Check a dataset's local cache against the server-side manifest`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("LlamaFarm Datasets Management")
 		cmd.Help()
@@ -66,61 +79,59 @@ var datasetsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all datasets on the server for the selected project",
 	Long:  `Lists datasets from the LlamaFarm server scoped by namespace/project.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Resolve server and routing
 		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		// Ensure server is up (auto-start locally if needed)
 		if err := ensureServerAvailable(serverCfg.URL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		url := buildServerURL(serverCfg.URL, fmt.Sprintf("/v1/projects/%s/%s/datasets/", serverCfg.Namespace, serverCfg.Project))
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrInternal, fmt.Errorf("creating request: %w", err))
 		}
 		resp, err := getHTTPClient().Do(req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error sending request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("sending request: %w", err))
 		}
 		defer resp.Body.Close()
 		body, readErr := io.ReadAll(resp.Body)
 		if resp.StatusCode != http.StatusOK {
 			if readErr != nil {
-				fmt.Fprintf(os.Stderr, "Error (%d), and body read failed: %v\n", resp.StatusCode, readErr)
-				os.Exit(1)
+				return errs.New(errs.ErrServer, fmt.Errorf("server returned error %d and body read failed: %w", resp.StatusCode, readErr))
 			}
-			fmt.Fprintf(os.Stderr, "Error (%d): %s\n", resp.StatusCode, prettyServerError(resp, body))
-			os.Exit(1)
+			return serverError(resp, body)
 		}
 
 		var out listDatasetsResponse
 		if err := json.Unmarshal(body, &out); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed parsing response: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServer, fmt.Errorf("parsing response: %w", err))
 		}
 
 		if out.Total == 0 {
 			fmt.Println("No datasets found.")
-			return
+			return nil
 		}
 
 		fmt.Printf("Found %d dataset(s):\n\n", out.Total)
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "NAME\tRAG STRATEGY\tFILE COUNT")
-		fmt.Fprintln(w, "----\t------------\t----------")
+		fmt.Fprintln(w, "NAME\tRAG STRATEGY\tFILE COUNT\tVERSION")
+		fmt.Fprintln(w, "----\t------------\t----------\t-------")
 		for _, ds := range out.Datasets {
-			fmt.Fprintf(w, "%s\t%s\t%d\n", ds.Name, emptyDefault(ds.RAGStrategy, "auto"), len(ds.Files))
+			version := "-"
+			if digest := lookupDatasetDigest(serverCfg.Namespace, serverCfg.Project, ds.Name); digest != "" {
+				version = "@sha256:" + digest[:12]
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", ds.Name, emptyDefault(ds.RAGStrategy, "auto"), len(ds.Files), version)
 		}
 		w.Flush()
+		return nil
 	},
 }
 
@@ -134,11 +145,10 @@ Examples:
   lf datasets add my-docs
   lf datasets add --rag-strategy auto my-pdfs ./pdfs/*.pdf`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		datasetName := args[0]
@@ -150,43 +160,37 @@ Examples:
 		payload, _ := json.Marshal(createReq)
 		// Ensure server is up
 		if err := ensureServerAvailable(serverCfg.URL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		url := buildServerURL(serverCfg.URL, fmt.Sprintf("/v1/projects/%s/%s/datasets/", serverCfg.Namespace, serverCfg.Project))
 		req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrInternal, fmt.Errorf("creating request: %w", err))
 		}
 		req.Header.Set("Content-Type", "application/json")
 		resp, err := getHTTPClient().Do(req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error sending request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("sending request: %w", err))
 		}
 		body, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
 			if readErr != nil {
-				fmt.Fprintf(os.Stderr, "Failed to create dataset '%s' (%d), and body read failed: %v\n", datasetName, resp.StatusCode, readErr)
-				os.Exit(1)
+				return errs.New(errs.ErrServer, fmt.Errorf("creating dataset '%s': server returned error %d and body read failed: %w", datasetName, resp.StatusCode, readErr))
 			}
-			fmt.Fprintf(os.Stderr, "Failed to create dataset '%s' (%d): %s\n", datasetName, resp.StatusCode, prettyServerError(resp, body))
-			os.Exit(1)
+			return serverError(resp, body)
 		}
 		var created createDatasetResponse
 		if err := json.Unmarshal(body, &created); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed parsing response: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServer, fmt.Errorf("parsing response: %w", err))
 		}
 		fmt.Printf("✅ Created dataset '%s' (rag: %s)\n", created.Dataset.Name, emptyDefault(created.Dataset.RAGStrategy, "auto"))
 
 		// 2) Optionally upload files if provided
 		filePaths := args[1:]
 		if len(filePaths) == 0 {
-			return
+			return nil
 		}
 		var filesToUpload []string
 		for _, p := range filePaths {
@@ -198,16 +202,11 @@ Examples:
 			}
 			filesToUpload = append(filesToUpload, matches...)
 		}
-		uploaded := 0
-		for _, fp := range filesToUpload {
-			if err := uploadFileToDataset(serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName, fp); err != nil {
-				fmt.Fprintf(os.Stderr, "   ⚠️  Failed to upload '%s': %v\n", fp, err)
-				continue
-			}
-			fmt.Printf("   📤 Uploaded: %s\n", fp)
-			uploaded++
-		}
+		ctx, stop := uploadSignalContext()
+		defer stop()
+		uploaded := uploadFilesConcurrent(ctx, serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName, filesToUpload, uploadParallel, !uploadNoProgress, uploadSilent)
 		fmt.Printf("   Done. Uploaded %d/%d file(s).\n", uploaded, len(filesToUpload))
+		return nil
 	},
 }
 
@@ -217,40 +216,35 @@ var datasetsRemoveCmd = &cobra.Command{
 	Short: "Delete a dataset from the server",
 	Long:  `Deletes a dataset from the LlamaFarm server for the selected project.`,
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 		datasetName := args[0]
 		// Ensure server is up
 		if err := ensureServerAvailable(serverCfg.URL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 		url := buildServerURL(serverCfg.URL, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s", serverCfg.Namespace, serverCfg.Project, datasetName))
 		req, err := http.NewRequest("DELETE", url, nil)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrInternal, fmt.Errorf("creating request: %w", err))
 		}
 		resp, err := getHTTPClient().Do(req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error sending request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("sending request: %w", err))
 		}
 		defer resp.Body.Close()
 		body, readErr := io.ReadAll(resp.Body)
 		if resp.StatusCode != http.StatusOK {
 			if readErr != nil {
-				fmt.Fprintf(os.Stderr, "Failed to remove dataset '%s' (%d), and body read failed: %v\n", datasetName, resp.StatusCode, readErr)
-				os.Exit(1)
+				return errs.New(errs.ErrServer, fmt.Errorf("removing dataset '%s': server returned error %d and body read failed: %w", datasetName, resp.StatusCode, readErr))
 			}
-			fmt.Fprintf(os.Stderr, "Failed to remove dataset '%s' (%d): %s\n", datasetName, resp.StatusCode, prettyServerError(resp, body))
-			os.Exit(1)
+			return serverError(resp, body)
 		}
 		fmt.Printf("✅ Successfully removed dataset '%s'\n", datasetName)
+		return nil
 	},
 }
 
@@ -260,17 +254,16 @@ var datasetsIngestCmd = &cobra.Command{
 	Short: "Upload files to a dataset on the server",
 	Long: `Uploads one or more files to the specified dataset on the LlamaFarm server.
 
+With --local-worker, files are instead streamed into a local sidecar
+ingest-worker container, so air-gapped users can ingest without a running
+LlamaFarm server.
+
 Examples:
   lf datasets ingest my-docs ./docs/file1.pdf ./docs/file2.txt
-  lf datasets ingest my-docs ./pdfs/*.pdf`,
+  lf datasets ingest my-docs ./pdfs/*.pdf
+  lf datasets ingest --local-worker my-docs ./pdfs/*.pdf`,
 	Args: cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		datasetName := args[0]
 		inPaths := args[1:]
 		var files []string
@@ -283,29 +276,260 @@ Examples:
 			files = append(files, matches...)
 		}
 		if len(files) == 0 {
-			fmt.Fprintf(os.Stderr, "No files to upload.\n")
-			os.Exit(1)
+			return errs.New(errs.ErrUser, fmt.Errorf("no files to upload"))
+		}
+
+		if ingestLocalWorker {
+			return ingestViaLocalWorker(datasetName, files, uploadSilent)
+		}
+
+		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
+		if err != nil {
+			return err
 		}
 
 		// Ensure server is up
 		if err := ensureServerAvailable(serverCfg.URL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Printf("Starting upload to dataset '%s' (%d file(s))...\n", datasetName, len(files))
-		uploaded := 0
-		for _, f := range files {
-			if err := uploadFileToDataset(serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName, f); err != nil {
-				fmt.Fprintf(os.Stderr, "   ⚠️  Failed to upload '%s': %v\n", f, err)
+			return err
+		}
+		if !uploadSilent {
+			fmt.Printf("Starting upload to dataset '%s' (%d file(s))...\n", datasetName, len(files))
+		}
+		ctx, stop := uploadSignalContext()
+		defer stop()
+		uploaded := uploadFilesConcurrent(ctx, serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName, files, uploadParallel, !uploadNoProgress, uploadSilent)
+		if !uploadSilent {
+			fmt.Printf("Done. Uploaded %d/%d file(s).\n", uploaded, len(files))
+		}
+		return nil
+	},
+}
+
+// ingestViaLocalWorker stages files into a sidecar ingest-worker container,
+// waits for it to report healthy, triggers ingestion, and polls until the
+// worker reports the batch done.
+func ingestViaLocalWorker(datasetName string, files []string, silent bool) error {
+	if err := ensureDockerAvailable(); err != nil {
+		return err
+	}
+
+	stagingDir, err := stageFilesForWorker(files)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	baseURL, err := startLocalIngestWorker(ctx, stagingDir)
+	if err != nil {
+		return fmt.Errorf("starting ingest worker: %w", err)
+	}
+
+	if !silent {
+		fmt.Println("Waiting for ingest worker to become healthy...")
+	}
+	if err := waitHealthy(baseURL, 60*time.Second); err != nil {
+		return err
+	}
+
+	relFiles := make([]string, len(files))
+	for i, f := range files {
+		relFiles[i] = stagedFileName(i, f)
+	}
+	if err := triggerWorkerIngest(baseURL, datasetName, relFiles); err != nil {
+		return fmt.Errorf("triggering ingest: %w", err)
+	}
+	if !silent {
+		fmt.Printf("Ingesting %d file(s) into '%s' via local worker...\n", len(files), datasetName)
+	}
+	if err := pollIngestProgress(baseURL, silent); err != nil {
+		return err
+	}
+	if !silent {
+		fmt.Printf("Done. Ingested %d file(s) via local worker.\n", len(files))
+	}
+	return nil
+}
+
+// datasetsPullCmd represents the datasets pull command
+var datasetsPullCmd = &cobra.Command{
+	Use:   "pull [name@version]",
+	Short: "Fetch a dataset artifact into the local cache",
+	Long: `Resolves a dataset reference like acme/legal-docs@v3, the way Helm
+resolves a chart: first the local cache under ~/.llamafarm/datasets/, then
+the registries configured in llamafarm.yaml's top-level 'registries' list.
+
+Examples:
+  lf datasets pull acme/legal-docs@v3
+  lf datasets pull acme/legal-docs         # defaults to @latest`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref := parseDatasetRef(args[0])
+		cacheDir, err := datasetCacheDir(ref)
+		if err != nil {
+			return errs.New(errs.ErrInternal, err)
+		}
+
+		if m, err := readCachedManifest(cacheDir); err == nil {
+			fmt.Printf("Using cached %s (%d file(s)) at %s\n", ref, len(m.Files), cacheDir)
+			return nil
+		}
+
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			cfg = &config.LlamaFarmConfig{}
+		}
+		if len(cfg.Registries) == 0 {
+			return errs.New(errs.ErrUser, fmt.Errorf("%s is not cached locally and no registries are configured in llamafarm.yaml", ref))
+		}
+
+		var lastErr error
+		for _, reg := range cfg.Registries {
+			m, err := fetchFromRegistry(reg, ref, cacheDir)
+			if err != nil {
+				lastErr = err
+				fmt.Fprintf(os.Stderr, "   registry %q: %v\n", reg.Name, err)
 				continue
 			}
-			fmt.Printf("   📤 Uploaded: %s\n", f)
-			uploaded++
+			if err := verifyDatasetSignature(cacheDir, pullKeyring); err != nil {
+				os.RemoveAll(cacheDir)
+				return errs.New(errs.ErrUser, err)
+			}
+			fmt.Printf("✅ Pulled %s from registry %q (%d file(s)) into %s\n", ref, reg.Name, len(m.Files), cacheDir)
+			return nil
+		}
+		return errs.New(errs.ErrServerUnavailable, fmt.Errorf("could not resolve %s from any configured registry: %w", ref, lastErr))
+	},
+}
+
+// datasetsPushCmd represents the datasets push command
+var datasetsPushCmd = &cobra.Command{
+	Use:   "push [name] [dir]",
+	Short: "Package a local directory as a dataset artifact and publish it",
+	Long: `Packages dir as a tarball with a manifest (name, rag_strategy, and
+every file's sha256), uploads it to the LlamaFarm server, and records the
+resulting digest so 'lf datasets list' can show it as a version.
+
+Example:
+  lf datasets push legal-docs ./corpus`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		datasetName := args[0]
+		dir := args[1]
+
+		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
+		if err != nil {
+			return err
+		}
+		if err := ensureServerAvailable(serverCfg.URL); err != nil {
+			return err
+		}
+
+		manifest, archivePath, err := packageDataset(datasetName, emptyDefault(ragStrategy, "auto"), dir)
+		if err != nil {
+			return errs.New(errs.ErrUser, fmt.Errorf("packaging '%s': %w", dir, err))
+		}
+		defer os.Remove(archivePath)
+
+		digest, err := publishDatasetPackage(serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName, archivePath)
+		if err != nil {
+			return errs.New(errs.ErrServer, fmt.Errorf("pushing '%s': %w", datasetName, err))
+		}
+		if digest == "" {
+			digest = manifest.Digest
+		}
+		if err := recordDatasetDigest(serverCfg.Namespace, serverCfg.Project, datasetName, digest); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: pushed successfully but failed to record digest locally: %v\n", err)
+		}
+		fmt.Printf("✅ Pushed %s (%d file(s)) as @sha256:%s\n", datasetName, len(manifest.Files), digest)
+		return nil
+	},
+}
+
+// datasetsVerifyCmd represents the datasets verify command
+var datasetsVerifyCmd = &cobra.Command{
+	Use:   "verify [name]",
+	Short: "Check a dataset's local cache against the server-side manifest",
+	Long: `Fetches the server-side manifest for the named dataset and, for
+every file, checks whether its digest is still present and intact in the
+local content-addressed cache (~/.llamafarm/cache/objects/), reporting any
+drift: missing blobs (never ingested from this machine, or since evicted)
+and corrupt blobs (present but no longer hashing to their own name).
+
+Example:
+  lf datasets verify my-docs`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		datasetName := args[0]
+		serverCfg, err := config.GetServerConfig(configFile, serverURL, namespace, projectID)
+		if err != nil {
+			return err
+		}
+		if err := ensureServerAvailable(serverCfg.URL); err != nil {
+			return err
+		}
+
+		manifest, err := fetchServerManifest(serverCfg.URL, serverCfg.Namespace, serverCfg.Project, datasetName)
+		if err != nil {
+			return errs.New(errs.ErrServer, fmt.Errorf("fetching manifest for '%s': %w", datasetName, err))
+		}
+		if len(manifest.Files) == 0 {
+			fmt.Printf("Dataset '%s' has no files.\n", datasetName)
+			return nil
+		}
+
+		var missing, corrupt int
+		for _, f := range manifest.Files {
+			status, err := datasetcache.Verify(f.SHA256)
+			switch {
+			case err != nil:
+				fmt.Printf("   ❓ %s: error checking cache: %v\n", f.Path, err)
+			case status == datasetcache.StatusMissing:
+				missing++
+				fmt.Printf("   ⚠️  %s: not in local cache (sha256:%s)\n", f.Path, f.SHA256)
+			case status == datasetcache.StatusCorrupt:
+				corrupt++
+				fmt.Printf("   ❌ %s: cached blob is corrupt (sha256:%s)\n", f.Path, f.SHA256)
+			}
+		}
+		if missing == 0 && corrupt == 0 {
+			fmt.Printf("✅ %s: all %d file(s) verified against local cache.\n", datasetName, len(manifest.Files))
+			return nil
 		}
-		fmt.Printf("Done. Uploaded %d/%d file(s).\n", uploaded, len(files))
+		fmt.Printf("Checked %d file(s): %d missing, %d corrupt.\n", len(manifest.Files), missing, corrupt)
+		return errs.New(errs.ErrUser, fmt.Errorf("%d file(s) missing, %d corrupt in local cache", missing, corrupt))
 	},
 }
 
+// fetchServerManifest fetches the dataset's manifest (per-file digests) from
+// the LlamaFarm server, for 'lf datasets verify' to diff against the local
+// cache.
+func fetchServerManifest(server, namespace, project, dataset string) (*datasetManifest, error) {
+	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/manifest", namespace, project, dataset))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, readErr := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		if readErr != nil {
+			return nil, readErr
+		}
+		return nil, fmt.Errorf("%s", prettyServerError(resp, body))
+	}
+	var m datasetManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest response: %w", err)
+	}
+	return &m, nil
+}
+
 func init() {
 	// Add persistent flags
 	datasetsCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file path (default: llamafarm.yaml in current directory)")
@@ -317,11 +541,26 @@ func init() {
 	// Add flags specific to add command
 	datasetsAddCmd.Flags().StringVarP(&ragStrategy, "rag-strategy", "r", "auto", "RAG strategy to use for this dataset (default: auto)")
 
+	// Upload flags, shared by any command that uploads files
+	for _, c := range []*cobra.Command{datasetsAddCmd, datasetsIngestCmd} {
+		c.Flags().BoolVar(&uploadNoProgress, "no-progress", false, "disable upload progress bars")
+		c.Flags().BoolVar(&uploadSilent, "silent", false, "suppress all upload output, including progress bars")
+		c.Flags().IntVar(&uploadParallel, "parallel", 1, "number of files to upload concurrently")
+	}
+
+	datasetsIngestCmd.Flags().BoolVar(&ingestLocalWorker, "local-worker", false, "ingest via a local sidecar worker container instead of uploading to a LlamaFarm server")
+
+	// Add flags specific to pull command
+	datasetsPullCmd.Flags().StringVar(&pullKeyring, "keyring", "", "verify the pulled package's detached signature against this public key file")
+
 	// Add subcommands to datasets
 	datasetsCmd.AddCommand(datasetsListCmd)
 	datasetsCmd.AddCommand(datasetsAddCmd)
 	datasetsCmd.AddCommand(datasetsRemoveCmd)
 	datasetsCmd.AddCommand(datasetsIngestCmd)
+	datasetsCmd.AddCommand(datasetsPullCmd)
+	datasetsCmd.AddCommand(datasetsPushCmd)
+	datasetsCmd.AddCommand(datasetsVerifyCmd)
 
 	// Add the datasets command to root
 	rootCmd.AddCommand(datasetsCmd)
@@ -334,48 +573,3 @@ func emptyDefault(s string, d string) string {
 	}
 	return s
 }
-
-func uploadFileToDataset(server string, namespace string, project string, dataset string, path string) error {
-	// Open file
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Prepare multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-	part, err := writer.CreateFormFile("file", filepath.Base(path))
-	if err != nil {
-		return err
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return err
-	}
-	if err := writer.Close(); err != nil {
-		return err
-	}
-
-	// Build request
-	url := buildServerURL(server, fmt.Sprintf("/v1/projects/%s/%s/datasets/%s/data", namespace, project, dataset))
-	req, err := http.NewRequest("POST", url, &buf)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := getHTTPClient().Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	body, readErr := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		if readErr != nil {
-			return fmt.Errorf("%s", readErr.Error())
-		}
-		return fmt.Errorf("%s", prettyServerError(resp, body))
-	}
-	return nil
-}
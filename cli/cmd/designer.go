@@ -1,18 +1,30 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
+
+	"llamafarm-cli/internal/runtime"
 
 	"github.com/spf13/cobra"
 )
 
+const managedDesignerContainerName = "llamafarm-designer"
+
 // designerCmd represents the designer command
 var designerCmd = &cobra.Command{
 	Use:   "designer",
 	Short: "Manage LlamaFarm designer environment",
-	Long:  `Commands for managing the LlamaFarm designer environment, including starting and stopping the llamafarm designer and runtime.`,
+	Long: `Commands for managing the LlamaFarm designer environment, including starting and stopping the llamafarm designer and runtime.
+
+Available commands:
+  start  - Start the designer container
+  stop   - Stop the running designer container
+  logs   - Print recent logs from the designer container
+  status - Report whether the designer container is running`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("LlamaFarm Designer")
 		cmd.Help()
@@ -67,6 +79,65 @@ var designerStartCmd = &cobra.Command{
 	},
 }
 
+var designerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the local LlamaFarm designer container",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := stopManagedContainer(ctx, managedDesignerContainerName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error stopping designer: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Stopped.")
+	},
+}
+
+var designerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report whether the local LlamaFarm designer container is running",
+	Run: func(cmd *cobra.Command, args []string) {
+		if isContainerRunning(managedDesignerContainerName) {
+			fmt.Println("running")
+			return
+		}
+		fmt.Println("not running")
+	},
+}
+
+var designerLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print recent logs from the local LlamaFarm designer container",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		client := runtime.NewClient("")
+		if err := client.Ping(ctx); err == nil {
+			rc, err := client.Logs(ctx, managedDesignerContainerName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching logs: %v\n", err)
+				os.Exit(1)
+			}
+			defer rc.Close()
+			runtime.CopyLogs(os.Stdout, os.Stderr, rc)
+			return
+		}
+
+		rt, err := detectContainerRuntime()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		out, err := containerLogsViaCLI(rt, managedDesignerContainerName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching logs: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(out)
+	},
+}
+
 func getCurrentDir() string {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -76,8 +147,11 @@ func getCurrentDir() string {
 }
 
 func init() {
-	// Add the start subcommand to designer
+	// Add subcommands to designer
 	designerCmd.AddCommand(designerStartCmd)
+	designerCmd.AddCommand(designerStopCmd)
+	designerCmd.AddCommand(designerStatusCmd)
+	designerCmd.AddCommand(designerLogsCmd)
 
 	// Add the designer command to root
 	rootCmd.AddCommand(designerCmd)
@@ -1,30 +1,43 @@
 package cmd
 
 import (
-    "errors"
+    "fmt"
     "os"
     "os/exec"
     "strings"
+
+    "llamafarm-cli/internal/errs"
 )
 
-// ensureDockerAvailable checks whether docker is available on PATH
+// ensureDockerAvailable checks whether a usable container runtime (Docker,
+// Podman, or nerdctl) is available on PATH, despite the Docker-specific
+// name: most callers pre-date the Podman/nerdctl fallback (see
+// container_runtime.go) and just want a yes/no answer.
 func ensureDockerAvailable() error {
-    if err := exec.Command("docker", "--version").Run(); err != nil {
-        return errors.New("docker is not available. Please install Docker and try again")
+    if _, err := detectContainerRuntime(); err != nil {
+        return errs.New(errs.ErrDockerMissing, fmt.Errorf("no container runtime available. Please install Docker, Podman, or nerdctl and try again: %w", err))
     }
     return nil
 }
 
-// pullImage pulls a docker image, streaming output to the current stdio
+// pullImage pulls an image using the detected container runtime, streaming
+// output to the current stdio. Falls back to the Docker CLI if detection
+// fails, preserving this function's behavior for callers on machines where
+// only `docker` happens to be on PATH.
 func pullImage(image string) error {
-    pullCmd := exec.Command("docker", "pull", image)
+    rt := runtimeOrDocker()
+    pullCmd := exec.Command(rt.Binary(), "pull", rt.NormalizeImage(image))
     pullCmd.Stdout = os.Stdout
     pullCmd.Stderr = os.Stderr
     return pullCmd.Run()
 }
 
 func containerExists(name string) bool {
-    cmd := exec.Command("docker", "ps", "-a", "--format", "{{.Names}}")
+    return containerExistsRT(runtimeOrDocker(), name)
+}
+
+func containerExistsRT(rt containerRuntime, name string) bool {
+    cmd := exec.Command(rt.Binary(), "ps", "-a", "--format", "{{.Names}}")
     out, err := cmd.Output()
     if err != nil {
         return false
@@ -38,7 +51,11 @@ func containerExists(name string) bool {
 }
 
 func isContainerRunning(name string) bool {
-    cmd := exec.Command("docker", "ps", "--format", "{{.Names}}")
+    return isContainerRunningRT(runtimeOrDocker(), name)
+}
+
+func isContainerRunningRT(rt containerRuntime, name string) bool {
+    cmd := exec.Command(rt.Binary(), "ps", "--format", "{{.Names}}")
     out, err := cmd.Output()
     if err != nil {
         return false
@@ -50,3 +67,26 @@ func isContainerRunning(name string) bool {
     }
     return false
 }
+
+// runtimeOrDocker returns the detected container runtime, falling back to
+// plain Docker when detection fails (e.g. nothing on PATH yet) so these
+// helpers degrade the same way they did before the Podman/nerdctl
+// abstraction existed, rather than panicking or changing error shape for
+// existing callers.
+func runtimeOrDocker() containerRuntime {
+    rt, err := detectContainerRuntime()
+    if err != nil {
+        return dockerRuntime{}
+    }
+    return rt
+}
+
+// containerLogsViaCLI returns recent logs for a container using the given
+// runtime's CLI, for use when the Engine API socket isn't reachable.
+func containerLogsViaCLI(rt containerRuntime, name string) (string, error) {
+    out, err := exec.Command(rt.Binary(), "logs", "--tail", "200", name).CombinedOutput()
+    if err != nil {
+        return "", fmt.Errorf("%v: %s", err, string(out))
+    }
+    return string(out), nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"llamafarm-cli/internal/errs"
+)
+
+// HandleError is the single place error formatting and the process exit
+// code are decided, for the commands that return an error from RunE
+// instead of calling os.Exit themselves (see initCmd, runCmd). --debug
+// prints the originating stack trace for a categorized error; --output=json
+// prints a single JSON object instead of plain text. The exit code is
+// derived from the error's category (see errs.ExitCode).
+func HandleError(err error) {
+	if err == nil {
+		return
+	}
+
+	if outputFormat == "json" {
+		printJSONError(err)
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	if debug {
+		if ce, ok := err.(*errs.CLIError); ok && ce.Stack != "" {
+			fmt.Fprintln(os.Stderr, ce.Stack)
+		}
+	}
+
+	os.Exit(errs.ExitCode(err))
+}
+
+func printJSONError(err error) {
+	out := struct {
+		Error  string         `json:"error"`
+		Code   string         `json:"code,omitempty"`
+		Detail map[string]any `json:"detail,omitempty"`
+	}{Error: err.Error()}
+
+	if ce, ok := err.(*errs.CLIError); ok {
+		out.Code = string(ce.Code)
+		out.Detail = ce.Detail
+	}
+
+	data, marshalErr := json.MarshalIndent(out, "", "  ")
+	if marshalErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
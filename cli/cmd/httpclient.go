@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"llamafarm-cli/internal/errs"
 )
 
 // HTTPClient interface for testing
@@ -26,7 +33,9 @@ func (c *DefaultHTTPClient) Do(req *http.Request) (*http.Response, error) {
 
 var httpClient HTTPClient = &DefaultHTTPClient{}
 
-// VerboseHTTPClient wraps another HTTPClient and logs request/response basics and headers.
+// VerboseHTTPClient wraps another HTTPClient and logs request/response basics
+// and headers to stderr when debug is on, and, when LLAMAFARM_HTTP_TRACE is
+// set, appends a structured, redacted record of every exchange to that file.
 type VerboseHTTPClient struct{ Inner HTTPClient }
 
 func (v *VerboseHTTPClient) Do(req *http.Request) (*http.Response, error) {
@@ -34,25 +43,204 @@ func (v *VerboseHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	if inner == nil {
 		inner = &DefaultHTTPClient{}
 	}
-	fmt.Fprintf(os.Stderr, "HTTP %s %s\n", req.Method, req.URL.String())
-	logHeaders("request", req.Header)
+	if debug {
+		fmt.Fprintf(os.Stderr, "HTTP %s %s\n", req.Method, req.URL.String())
+		logHeaders("request", req.Header)
+	}
+
+	tf := httpTraceWriter()
+	var reqBody *cappedBuffer
+	if tf != nil && req.Body != nil {
+		reqBody = &cappedBuffer{cap: httpTraceBodyCap()}
+		req.Body = io.NopCloser(io.TeeReader(req.Body, reqBody))
+	}
+	start := time.Now()
+
 	resp, err := inner.Do(req)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "  -> error: %v\n", err)
+		if debug {
+			fmt.Fprintf(os.Stderr, "  -> error: %v\n", err)
+		}
+		if tf != nil {
+			writeHTTPTrace(tf, req, reqBody, nil, nil, start)
+		}
 		return nil, err
 	}
-	fmt.Fprintf(os.Stderr, "  -> %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
-	logHeaders("response", resp.Header)
+
+	if debug {
+		fmt.Fprintf(os.Stderr, "  -> %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+		logHeaders("response", resp.Header)
+	}
+
+	if tf != nil {
+		respBody := &cappedBuffer{cap: httpTraceBodyCap()}
+		underlying := resp.Body
+		var once sync.Once
+		resp.Body = &traceReadCloser{
+			Reader: io.TeeReader(underlying, respBody),
+			closer: underlying,
+			finalize: func() {
+				once.Do(func() { writeHTTPTrace(tf, req, reqBody, resp, respBody, start) })
+			},
+		}
+	}
 	return resp, nil
 }
 
 func getHTTPClient() HTTPClient {
-	if debug {
+	if debug || os.Getenv("LLAMAFARM_HTTP_TRACE") != "" {
 		return &VerboseHTTPClient{Inner: httpClient}
 	}
 	return httpClient
 }
 
+const defaultHTTPTraceBodyCap = 64 * 1024
+
+var (
+	httpTraceOnce    sync.Once
+	httpTraceFile    *os.File
+	httpTraceRedact  *regexp.Regexp
+	alwaysRedactHdrs = map[string]bool{"authorization": true, "cookie": true, "set-cookie": true}
+)
+
+// httpTraceWriter lazily opens the file named by LLAMAFARM_HTTP_TRACE (if
+// any) the first time it's needed, and also compiles LLAMAFARM_HTTP_TRACE_REDACT
+// if set. Returns nil when tracing is disabled or the file can't be opened.
+func httpTraceWriter() *os.File {
+	httpTraceOnce.Do(func() {
+		path := os.Getenv("LLAMAFARM_HTTP_TRACE")
+		if path == "" {
+			return
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "http trace: failed to open %s: %v\n", path, err)
+			return
+		}
+		httpTraceFile = f
+		if pattern := os.Getenv("LLAMAFARM_HTTP_TRACE_REDACT"); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "http trace: invalid LLAMAFARM_HTTP_TRACE_REDACT pattern: %v\n", err)
+			} else {
+				httpTraceRedact = re
+			}
+		}
+	})
+	return httpTraceFile
+}
+
+// httpTraceBodyCap returns the number of bytes of request/response body to
+// capture per exchange, overridable via LLAMAFARM_HTTP_TRACE_BODY_CAP.
+func httpTraceBodyCap() int {
+	if v := os.Getenv("LLAMAFARM_HTTP_TRACE_BODY_CAP"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultHTTPTraceBodyCap
+}
+
+// cappedBuffer collects up to cap bytes and silently drops the rest, so
+// tracing a large streamed body never grows the trace file unbounded.
+type cappedBuffer struct {
+	buf     bytes.Buffer
+	cap     int
+	dropped bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := c.cap - c.buf.Len()
+	if remaining <= 0 {
+		c.dropped = true
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		c.buf.Write(p[:remaining])
+		c.dropped = true
+		return len(p), nil
+	}
+	c.buf.Write(p)
+	return len(p), nil
+}
+
+func (c *cappedBuffer) String() string {
+	if c.dropped {
+		return c.buf.String() + "...(truncated)"
+	}
+	return c.buf.String()
+}
+
+// traceReadCloser tees a response body into a cappedBuffer as it's read by
+// the caller, then finalizes the trace record (including whatever of the
+// body was actually consumed) when the caller closes it.
+type traceReadCloser struct {
+	io.Reader
+	closer   io.Closer
+	finalize func()
+}
+
+func (t *traceReadCloser) Close() error {
+	t.finalize()
+	return t.closer.Close()
+}
+
+// redactHeaders copies hdr, replacing Authorization/Cookie/Set-Cookie and
+// anything matching LLAMAFARM_HTTP_TRACE_REDACT with "***".
+func redactHeaders(hdr http.Header) map[string][]string {
+	out := make(map[string][]string, len(hdr))
+	for k, vals := range hdr {
+		if alwaysRedactHdrs[strings.ToLower(k)] || (httpTraceRedact != nil && httpTraceRedact.MatchString(k)) {
+			out[k] = []string{"***"}
+			continue
+		}
+		out[k] = append([]string{}, vals...)
+	}
+	return out
+}
+
+// httpTraceRecord is one newline-delimited JSON line written to the
+// LLAMAFARM_HTTP_TRACE file per request/response exchange.
+type httpTraceRecord struct {
+	Timestamp   string              `json:"ts"`
+	Dir         string              `json:"dir"`
+	Method      string              `json:"method"`
+	URL         string              `json:"url"`
+	Status      int                 `json:"status,omitempty"`
+	DurationMS  int64               `json:"duration_ms"`
+	ReqHeaders  map[string][]string `json:"req_headers,omitempty"`
+	RespHeaders map[string][]string `json:"resp_headers,omitempty"`
+	ReqBody     string              `json:"req_body,omitempty"`
+	RespBody    string              `json:"resp_body,omitempty"`
+}
+
+func writeHTTPTrace(f *os.File, req *http.Request, reqBody *cappedBuffer, resp *http.Response, respBody *cappedBuffer, start time.Time) {
+	rec := httpTraceRecord{
+		Timestamp:  start.UTC().Format(time.RFC3339Nano),
+		Dir:        "out",
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		DurationMS: time.Since(start).Milliseconds(),
+		ReqHeaders: redactHeaders(req.Header),
+	}
+	if reqBody != nil {
+		rec.ReqBody = reqBody.String()
+	}
+	if resp != nil {
+		rec.Status = resp.StatusCode
+		rec.RespHeaders = redactHeaders(resp.Header)
+	}
+	if respBody != nil {
+		rec.RespBody = respBody.String()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "http trace: failed to marshal record: %v\n", err)
+		return
+	}
+	_, _ = f.Write(append(line, '\n'))
+}
+
 func logHeaders(kind string, hdr http.Header) {
 	if len(hdr) == 0 {
 		return
@@ -117,3 +305,21 @@ func prettyServerError(resp *http.Response, body []byte) string {
 	}
 	return s
 }
+
+// serverError builds a categorized *errs.CLIError from a failed HTTP
+// response, carrying the status code and parsed message as structured
+// detail fields for --output=json consumers, on top of prettyServerError's
+// plain-text message.
+func serverError(resp *http.Response, body []byte) *errs.CLIError {
+	msg := prettyServerError(resp, body)
+	code := errs.ErrServer
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		code = errs.ErrAuth
+	case http.StatusNotFound:
+		code = errs.ErrProjectNotFound
+	}
+	return errs.New(code, fmt.Errorf("server returned error %d: %s", resp.StatusCode, msg)).
+		WithDetail("status", resp.StatusCode).
+		WithDetail("message", msg)
+}
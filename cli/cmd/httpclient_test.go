@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -32,3 +38,92 @@ func TestPrettyServerError_AlternateShapes(t *testing.T) {
 		t.Fatalf("want 'deep', got %q", got)
 	}
 }
+
+// resetHTTPTrace clears the package-level trace state so each test gets a
+// fresh httpTraceWriter() initialization keyed off its own env vars.
+func resetHTTPTrace(t *testing.T) {
+	t.Helper()
+	httpTraceOnce = sync.Once{}
+	httpTraceFile = nil
+	httpTraceRedact = nil
+}
+
+// readingClient simulates a real transport by fully consuming the request
+// body before returning the canned response, the way net/http.Client does.
+type readingClient struct {
+	resp *http.Response
+	err  error
+}
+
+func (r *readingClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}
+	return r.resp, r.err
+}
+
+func TestVerboseHTTPClient_Trace(t *testing.T) {
+	resetHTTPTrace(t)
+	tracePath := filepath.Join(t.TempDir(), "trace.ndjson")
+	t.Setenv("LLAMAFARM_HTTP_TRACE", tracePath)
+	t.Setenv("LLAMAFARM_HTTP_TRACE_REDACT", "^X-Api-Key$")
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+	}
+	d := &readingClient{resp: resp}
+	prev := httpClient
+	httpClient = d
+	prevDebug := debug
+	debug = false
+	defer func() { httpClient = prev; debug = prevDebug }()
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:8000/foo", strings.NewReader(`{"secret":"x"}`))
+	req.Header.Set("Authorization", "Bearer shh")
+	req.Header.Set("X-Api-Key", "shh-too")
+
+	client := getHTTPClient()
+	gotResp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(gotResp.Body)
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("tracing should not alter the body seen by the caller, got %q", body)
+	}
+	if err := gotResp.Body.Close(); err != nil {
+		t.Fatalf("unexpected error closing body: %v", err)
+	}
+
+	data, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("expected a trace file to be written: %v", err)
+	}
+	var rec map[string]any
+	if err := json.Unmarshal(data[:len(data)-1], &rec); err != nil {
+		t.Fatalf("expected a single NDJSON record, got %q: %v", data, err)
+	}
+	if rec["status"] != float64(200) || rec["method"] != "POST" {
+		t.Fatalf("unexpected trace record: %v", rec)
+	}
+	reqHeaders, _ := rec["req_headers"].(map[string]any)
+	if vals, _ := reqHeaders["Authorization"].([]any); len(vals) != 1 || vals[0] != "***" {
+		t.Fatalf("expected Authorization to be redacted, got %v", reqHeaders["Authorization"])
+	}
+	if vals, _ := reqHeaders["X-Api-Key"].([]any); len(vals) != 1 || vals[0] != "***" {
+		t.Fatalf("expected X-Api-Key to be redacted via custom pattern, got %v", reqHeaders["X-Api-Key"])
+	}
+	if rec["req_body"] != `{"secret":"x"}` || rec["resp_body"] != `{"ok":true}` {
+		t.Fatalf("expected captured request/response bodies, got %v", rec)
+	}
+}
+
+func TestCappedBuffer_Truncates(t *testing.T) {
+	c := &cappedBuffer{cap: 4}
+	_, _ = c.Write([]byte("hello world"))
+	if got := c.String(); got != "hell...(truncated)" {
+		t.Fatalf("expected truncated output, got %q", got)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+
+	"llamafarm-cli/internal/errs"
+	"llamafarm-cli/internal/starter"
 
 	"github.com/spf13/cobra"
 )
@@ -18,7 +22,7 @@ var initCmd = &cobra.Command{
 	Short: "Initialize a new LlamaFarm project",
 	Long:  `Initialize a new LlamaFarm project in the current directory (or a target path).`,
 	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Println("Initializing a new LlamaFarm project...")
 
 		// Determine target directory
@@ -26,22 +30,28 @@ var initCmd = &cobra.Command{
 		if len(args) > 0 {
 			projectDir = args[0]
 		}
+		if initPrintConfig {
+			configPath := filepath.Join(projectDir, "llamafarm.yaml")
+			if err := printEffectiveConfig(configPath); err != nil {
+				return errs.New(errs.ErrConfigInvalid, err)
+			}
+			return nil
+		}
+
 		if projectDir != "." {
 			if err := os.MkdirAll(projectDir, 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to create directory %s: %v\n", projectDir, err)
-				os.Exit(1)
+				return errs.New(errs.ErrUser, fmt.Errorf("failed to create directory %s: %w", projectDir, err))
 			}
 		}
 
 		// Derive project name from directory
 		var projectName string
 		if projectDir == "." {
-			if wd, err := os.Getwd(); err == nil {
-				projectName = filepath.Base(wd)
-			} else {
-				fmt.Fprintf(os.Stderr, "Failed to determine working directory: %v\n", err)
-				os.Exit(1)
+			wd, err := os.Getwd()
+			if err != nil {
+				return errs.New(errs.ErrInternal, fmt.Errorf("failed to determine working directory: %w", err))
 			}
+			projectName = filepath.Base(wd)
 		} else {
 			projectName = filepath.Base(projectDir)
 		}
@@ -57,8 +67,7 @@ var initCmd = &cobra.Command{
 			base = "http://localhost:8000"
 		}
 		if err := ensureServerAvailable(base); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("ensuring server availability: %w", err))
 		}
 
 		// Build URL
@@ -81,8 +90,7 @@ var initCmd = &cobra.Command{
 		needChdir := projectDir != "."
 		if needChdir {
 			if err := os.Chdir(projectDir); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to change directory to %s: %v\n", projectDir, err)
-				os.Exit(1)
+				return errs.New(errs.ErrUser, fmt.Errorf("failed to change directory to %s: %w", projectDir, err))
 			}
 			defer func() { _ = os.Chdir(origWD) }()
 		}
@@ -90,22 +98,23 @@ var initCmd = &cobra.Command{
 		// Create request
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(bodyBytes))
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrInternal, fmt.Errorf("error creating request: %w", err))
 		}
 		req.Header.Set("Content-Type", "application/json")
 
 		// Execute
 		resp, err := getHTTPClient().Do(req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error contacting server: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("error contacting server: %w", err))
 		}
 		defer resp.Body.Close()
 		respBody, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			fmt.Fprintf(os.Stderr, "Server returned error %d: %s\n", resp.StatusCode, prettyServerError(resp, respBody))
-			os.Exit(1)
+			return serverError(resp, respBody)
+		}
+
+		if initConfigTemplate != "" {
+			renderStarterTemplate(initConfigTemplate, ns, projectName)
 		}
 
 		// Success message
@@ -120,6 +129,7 @@ var initCmd = &cobra.Command{
 			}
 		}
 		fmt.Printf("Created project %s/%s in %s\n", ns, projectName, absPath)
+		return nil
 	},
 }
 
@@ -127,6 +137,31 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().StringVar(&namespace, "namespace", "", "Project namespace")
 	initCmd.Flags().StringVar(&initConfigTemplate, "template", "", "Configuration template to use (optional)")
+	initCmd.Flags().BoolVar(&initPrintConfig, "print-config", false, "Print the fully merged effective llamafarm.yaml and exit")
 }
 
 var initConfigTemplate string
+var initPrintConfig bool
+
+// renderStarterTemplate resolves ref as a local starter bundle (a builtin
+// name, a directory, or a cached/fetched remote — see internal/starter) and
+// renders its files into the current directory. A resolution failure is
+// treated as non-fatal: ref may be a template name the server already
+// understood via config_template, so init's success shouldn't hinge on the
+// CLI also recognizing it.
+func renderStarterTemplate(ref, ns, projectName string) {
+	srcFS, root, err := starter.Resolve(ref)
+	if err != nil {
+		return
+	}
+	vars := starter.TemplateVars{
+		ProjectName: projectName,
+		Namespace:   ns,
+		Now:         time.Now().Format(time.RFC3339),
+	}
+	if err := starter.Render(srcFS, root, ".", vars); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to render starter template %s: %v\n", ref, err)
+		return
+	}
+	fmt.Printf("Rendered starter template %s\n", ref)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logLevel and logFormat back the --log-level/--log-format persistent
+// flags (see root.go's init). Empty means "use LLAMAFARM_LOG_LEVEL, or the
+// defaults" — see initLogger.
+var (
+	logLevel  string
+	logFormat string
+)
+
+// logger is the package-level structured logger for operational events
+// (server URL, namespace/project, HTTP status, latency, session id) across
+// command actions. User-facing output (chat prompts, list output) is
+// unaffected: it's printed directly to stdout, not through logger.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// initLogger rebuilds the package-level logger from --log-level/--log-format,
+// falling back to the LLAMAFARM_LOG_LEVEL environment variable when
+// --log-level wasn't set, and to info/text when neither was. Called once
+// from rootCmd's PersistentPreRun, after flags are parsed.
+func initLogger() {
+	level := logLevel
+	if level == "" {
+		level = os.Getenv("LLAMAFARM_LOG_LEVEL")
+	}
+
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	if strings.ToLower(logFormat) == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+	logger = slog.New(handler)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestInitLogger_LevelFromFlagAndEnv(t *testing.T) {
+	defer func() { logLevel, logFormat = "", "text" }()
+
+	logLevel = "debug"
+	logFormat = "json"
+	initLogger()
+	if !logger.Enabled(nil, slog.LevelDebug) {
+		t.Fatalf("expected debug level to be enabled")
+	}
+
+	logLevel = ""
+	t.Setenv("LLAMAFARM_LOG_LEVEL", "warn")
+	initLogger()
+	if logger.Enabled(nil, slog.LevelInfo) {
+		t.Fatalf("expected info level to be disabled when LLAMAFARM_LOG_LEVEL=warn")
+	}
+	if !logger.Enabled(nil, slog.LevelWarn) {
+		t.Fatalf("expected warn level to be enabled when LLAMAFARM_LOG_LEVEL=warn")
+	}
+}
@@ -2,27 +2,45 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
+	"time"
 
 	"llamafarm-cli/cmd/config"
+	"llamafarm-cli/internal/errs"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	namespace   string
-	projectID   string
-	sessionID   string
-	temperature float64
-	maxTokens   int
-	streaming   bool
+	namespace         string
+	projectID         string
+	sessionID         string
+	temperature       float64
+	maxTokens         int
+	streaming         bool
+	streamTimeoutSecs int
+
+	// Scriptable one-shot mode flags; see chat_script.go.
+	chatPrompt     string
+	chatPromptFile string
+	chatInput      string
+	chatSystem     string
+	chatSystemFile string
+	chatOutput     string
+	chatNoStream   bool
+	chatRaw        bool
+
+	// Tool-calling flags; see chat_tools.go.
+	chatToolsFile   string
+	chatToolConfirm bool
 )
 
 // Chat client types and helpers are defined in chat_client.go
@@ -46,50 +64,48 @@ var projectsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List projects in a namespace",
 	Long:  "List projects available in the specified namespace on the LlamaFarm server.",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Resolve config path from persistent flag
 		configPath, _ := cmd.Flags().GetString("config")
 
 		// Resolve server URL and namespace (project is not required for list)
 		serverCfg, err := config.GetServerConfigLenient(configPath, serverURL, namespace, "")
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 		serverURL = serverCfg.URL
 		ns := strings.TrimSpace(serverCfg.Namespace)
 
 		if ns == "" {
-			fmt.Fprintln(os.Stderr, "Error: namespace is required. Provide --namespace or set it in llamafarm.yaml")
-			os.Exit(1)
+			return errs.New(errs.ErrUser, fmt.Errorf("namespace is required; provide --namespace or set it in llamafarm.yaml"))
 		}
 
 		// Ensure server is up (auto-start locally if needed)
 		if err := ensureServerAvailable(serverURL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		// Build request
 		url := buildServerURL(serverURL, fmt.Sprintf("/v1/projects/%s", ns))
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating request: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrInternal, fmt.Errorf("creating list request: %w", err))
 		}
 
 		// Execute
+		start := time.Now()
 		resp, err := getHTTPClient().Do(req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error requesting server: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServerUnavailable, fmt.Errorf("requesting server: %w", err))
 		}
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
+		latency := time.Since(start)
 		if resp.StatusCode != http.StatusOK {
-			fmt.Fprintf(os.Stderr, "Server returned error %d: %s\n", resp.StatusCode, string(body))
-			os.Exit(1)
+			logger.Error("server returned error", "url", url, "status", resp.StatusCode, "latency_ms", latency.Milliseconds(), "body", string(body))
+			return serverError(resp, body)
 		}
+		logger.Info("listed projects", "url", url, "namespace", ns, "status", resp.StatusCode, "latency_ms", latency.Milliseconds())
 
 		var listResp struct {
 			Total    int `json:"total"`
@@ -99,18 +115,18 @@ var projectsListCmd = &cobra.Command{
 			} `json:"projects"`
 		}
 		if err := json.Unmarshal(body, &listResp); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to parse server response: %v\n", err)
-			os.Exit(1)
+			return errs.New(errs.ErrServer, fmt.Errorf("parsing server response: %w", err))
 		}
 
 		if listResp.Total == 0 || len(listResp.Projects) == 0 {
 			fmt.Printf("No projects found in namespace %s\n", ns)
-			return
+			return nil
 		}
 
 		for _, p := range listResp.Projects {
 			fmt.Printf("%s/%s\n", p.Namespace, p.Name)
 		}
+		return nil
 	},
 }
 
@@ -127,58 +143,101 @@ Examples:
   lf projects chat --server-url http://localhost:8000       # Override server URL
   lf projects chat --namespace my-org --project my-project  # Override project settings
   lf projects chat --config /path/to/config.yaml            # Use specific config file`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get the config file path from the flag
 		configPath, _ := cmd.Flags().GetString("config")
 
 		// Get server configuration (lenient: namespace/project optional)
 		serverConfig, err := config.GetServerConfigLenient(configPath, serverURL, namespace, projectID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		// Ensure server is up (auto-start locally if needed)
 		if err := ensureServerAvailable(serverURL); err != nil {
-			fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		// Update global variables with resolved values
 		serverURL = serverConfig.URL
 		namespace = serverConfig.Namespace
 		projectID = serverConfig.Project
+		logger.Info("starting chat session", "server_url", serverURL, "namespace", namespace, "project", projectID, "session_id", sessionID)
+
+		if chatToolsFile != "" {
+			specs, err := loadChatTools(chatToolsFile)
+			if err != nil {
+				return errs.New(errs.ErrUser, fmt.Errorf("loading tools file %q: %w", chatToolsFile, err))
+			}
+			chatToolSpecsGlobal = specs
+			chatToolDefsGlobal = chatToolDefs(specs)
+			logger.Info("loaded chat tools", "path", chatToolsFile, "count", len(specs))
+		}
+
+		opts := chatScriptOptions{
+			Prompt:     chatPrompt,
+			PromptFile: chatPromptFile,
+			Input:      chatInput,
+			System:     chatSystem,
+			SystemFile: chatSystemFile,
+			Output:     chatOutput,
+			NoStream:   chatNoStream,
+			Raw:        chatRaw,
+		}
+		if opts.isScriptable() {
+			os.Exit(runScriptableChat(opts))
+		}
 
 		startChatSession()
+		return nil
 	},
 }
 
 func startChatSession() {
-	// Handle Ctrl+C gracefully
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		fmt.Print("\n^C\n")
-		// Try to end the server session gracefully (best-effort)
-		if sessionID != "" {
-			_ = deleteChatSession()
-		}
-		fmt.Println("👋 You have left the pasture. Safe travels, little llama!")
-		os.Exit(0)
-	}()
-	fmt.Printf("🌾 Starting LlamaFarm chat session...\n")
-	fmt.Printf("📡 Server: %s\n", serverURL)
+	printChatBanner(fmt.Printf)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		startChatSessionInteractive()
+		return
+	}
+	// Not a TTY (piped input, CI, etc.): raw mode and line editing don't
+	// apply, so fall back to the plain scanner-based REPL.
+	startChatSessionPlain()
+}
+
+// printChatBanner prints the session header through print, so both the
+// plain (fmt.Printf) and raw-mode (chatTerminal.Printf) front ends can share
+// it.
+func printChatBanner(print func(format string, args ...any) (int, error)) {
+	print("🌾 Starting LlamaFarm chat session...\n")
+	print("📡 Server: %s\n", serverURL)
 	if namespace != "" || projectID != "" {
-		fmt.Printf("📁 Project: %s/%s\n", namespace, projectID)
+		print("📁 Project: %s/%s\n", namespace, projectID)
 	} else {
-		fmt.Printf("📁 Project: (not specified)\n")
+		print("📁 Project: (not specified)\n")
 	}
 	if sessionID != "" {
-		fmt.Printf("🆔 Session: %s\n", sessionID)
+		print("🆔 Session: %s\n", sessionID)
 	}
-	fmt.Printf("\nType 'exit' or 'quit' to end the session, 'clear' to start a new session.\n")
-	fmt.Printf("Type your message and press Enter to send.\n\n")
+	print("\nType 'exit' or 'quit' to end the session, 'clear' to start a new session.\n")
+	print("Type your message and press Enter to send.\n\n")
+}
+
+// startChatSessionPlain is the non-interactive chat REPL: a plain
+// bufio.Scanner loop with no line editing, history, or per-request
+// cancellation. Used when stdin isn't a terminal.
+//
+// Ctrl-C here is handled through installSignalCleanup's single signal path
+// (see onShutdown) instead of a dedicated signal.Notify, so it can't race
+// the global handler over which one ends the session and exits first.
+func startChatSessionPlain() {
+	unregister := onShutdown(func() {
+		fmt.Print("\n^C\n")
+		if sessionID != "" {
+			_ = deleteChatSession()
+		}
+		fmt.Println("👋 You have left the pasture. Safe travels, little llama!")
+	})
+	defer unregister()
 
 	var conversationHistory []ChatMessage
 	scanner := bufio.NewScanner(os.Stdin)
@@ -194,7 +253,6 @@ func startChatSession() {
 			continue
 		}
 
-		// Handle special commands
 		switch strings.ToLower(userInput) {
 		case "exit", "quit":
 			fmt.Println("👋 Goodbye!")
@@ -209,36 +267,35 @@ func startChatSession() {
 			continue
 		}
 
-		// Add user message to conversation history
-		userMessage := ChatMessage{
-			Role:    "user",
-			Content: userInput,
-		}
+		userMessage := ChatMessage{Role: "user", Content: userInput}
 		conversationHistory = append(conversationHistory, userMessage)
 
-		// Send request to server
 		fmt.Print("Assistant: ")
 		if streaming {
-			assistantMessage, err := sendChatRequestStream(conversationHistory)
+			_, err := runChatTurn(&conversationHistory, fmt.Printf, func(h []ChatMessage) (string, error) {
+				return sendChatRequestStream(context.Background(), os.Stdout, h)
+			})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				continue
 			}
 			fmt.Printf("\n\n")
-			conversationHistory = append(conversationHistory, ChatMessage{Role: "assistant", Content: assistantMessage})
 		} else {
-			response, err := sendChatRequest(conversationHistory)
+			assistantMessage, err := runChatTurn(&conversationHistory, fmt.Printf, func(h []ChatMessage) (string, error) {
+				response, err := sendChatRequest(h)
+				if err != nil {
+					return "", err
+				}
+				if len(response.Choices) == 0 {
+					return "", fmt.Errorf("no response received")
+				}
+				return response.Choices[0].Message.Content, nil
+			})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				continue
 			}
-			if len(response.Choices) > 0 {
-				assistantMessage := response.Choices[0].Message.Content
-				fmt.Printf("%s\n\n", assistantMessage)
-				conversationHistory = append(conversationHistory, ChatMessage{Role: "assistant", Content: assistantMessage})
-			} else {
-				fmt.Println("No response received.")
-			}
+			fmt.Printf("%s\n\n", assistantMessage)
 		}
 	}
 
@@ -247,13 +304,209 @@ func startChatSession() {
 	}
 }
 
+// startChatSessionInteractive is the raw-mode chat REPL: line editing,
+// persistent history with Ctrl-R search, /multiline, /save, /load, and a
+// Ctrl-C that cancels only the in-flight streamed response. term.Terminal
+// treats Ctrl-C at the prompt as an immediate end of input (same as Ctrl-D),
+// so there's no separate "press twice" step there; it's only while a
+// response is streaming, when nothing is reading the prompt, that Ctrl-C
+// needs the explicit watchStreamInterrupt handling below.
+func startChatSessionInteractive() {
+	t, err := newChatTerminal()
+	if err != nil {
+		// Raw mode failed even though IsTerminal said yes (e.g. stdin
+		// redirected from something term-like but not controllable);
+		// fall back rather than leaving the user stuck.
+		fmt.Fprintf(os.Stderr, "Warning: could not enter raw terminal mode (%v), falling back to plain input.\n", err)
+		startChatSessionPlain()
+		return
+	}
+	defer t.Close()
+	stopResize := watchResize(t)
+	defer stopResize()
+
+	var conversationHistory []ChatMessage
+
+	for {
+		line, err := t.ReadLine(ansiBold + ansiCyan + "You: " + ansiReset)
+		if err != nil {
+			// io.EOF covers both Ctrl-D and Ctrl-C (term.Terminal maps
+			// both to end-of-input); any other read error also ends
+			// the session.
+			break
+		}
+
+		userInput := strings.TrimSpace(line)
+		if userInput == "" {
+			continue
+		}
+
+		if strings.HasPrefix(userInput, "/") || strings.EqualFold(userInput, "help") {
+			handled, done := handleChatCommand(t, userInput, &conversationHistory)
+			if done {
+				break
+			}
+			if handled {
+				continue
+			}
+		}
+
+		t.Remember(userInput)
+		conversationHistory = append(conversationHistory, ChatMessage{Role: "user", Content: userInput})
+
+		t.Printf(ansiBold + ansiGreen + "Assistant:" + ansiReset + " ")
+		reqCtx, cancel := context.WithCancel(context.Background())
+		cancelOnInterrupt := watchStreamInterrupt(cancel)
+		_, err = runChatTurn(&conversationHistory, t.Printf, func(h []ChatMessage) (string, error) {
+			return sendChatRequestStream(reqCtx, t.term, h)
+		})
+		cancelOnInterrupt()
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				t.Printf("\n" + ansiYellow + "(cancelled)" + ansiReset + "\n\n")
+			} else {
+				t.Printf("\n"+ansiYellow+"Error: %v"+ansiReset+"\n\n", err)
+			}
+			continue
+		}
+		t.Printf("\n\n")
+	}
+
+	if sessionID != "" {
+		_ = deleteChatSession()
+	}
+	fmt.Println("\n👋 You have left the pasture. Safe travels, little llama!")
+}
+
+// handleChatCommand processes a leading-"/" chat command (plus the bare
+// "help"/"exit"/"quit"/"clear" words for parity with the plain REPL). It
+// reports whether userInput was a command at all (handled) and whether the
+// session should end (done).
+func handleChatCommand(t *chatTerminal, userInput string, history *[]ChatMessage) (handled, done bool) {
+	fields := strings.Fields(userInput)
+	cmd := strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	switch cmd {
+	case "exit", "quit":
+		return true, true
+	case "clear":
+		*history = nil
+		sessionID = ""
+		t.Printf(ansiYellow + "🧹 Session cleared. Starting fresh conversation." + ansiReset + "\n")
+		return true, false
+	case "help":
+		printChatHelpTo(t.Printf)
+		return true, false
+	case "multiline":
+		body, err := readMultilineBody(t)
+		if err != nil {
+			t.Printf(ansiYellow+"Error reading multiline input: %v"+ansiReset+"\n", err)
+			return true, false
+		}
+		body = strings.TrimSpace(body)
+		if body == "" {
+			return true, false
+		}
+		t.Remember(body)
+		*history = append(*history, ChatMessage{Role: "user", Content: body})
+		t.Printf(ansiGreen + "Assistant:" + ansiReset + " ")
+		_, err = runChatTurn(history, t.Printf, func(h []ChatMessage) (string, error) {
+			return sendChatRequestStream(context.Background(), t.term, h)
+		})
+		if err != nil {
+			t.Printf("\n"+ansiYellow+"Error: %v"+ansiReset+"\n\n", err)
+			return true, false
+		}
+		t.Printf("\n\n")
+		return true, false
+	case "save":
+		if len(fields) < 2 {
+			t.Printf(ansiYellow + "Usage: /save <path>" + ansiReset + "\n")
+			return true, false
+		}
+		if err := saveChatTranscript(fields[1], *history); err != nil {
+			t.Printf(ansiYellow+"Error: %v"+ansiReset+"\n", err)
+		} else {
+			t.Printf("💾 Saved conversation to %s\n", fields[1])
+		}
+		return true, false
+	case "load":
+		if len(fields) < 2 {
+			t.Printf(ansiYellow + "Usage: /load <path>" + ansiReset + "\n")
+			return true, false
+		}
+		loaded, err := loadChatTranscript(fields[1])
+		if err != nil {
+			t.Printf(ansiYellow+"Error: %v"+ansiReset+"\n", err)
+			return true, false
+		}
+		*history = loaded
+		t.Printf("📂 Loaded conversation from %s (%d messages)\n", fields[1], len(loaded))
+		return true, false
+	default:
+		if strings.HasPrefix(userInput, "/") {
+			t.Printf(ansiYellow+"Unknown command: %s (try /help)"+ansiReset+"\n", fields[0])
+			return true, false
+		}
+		return false, false
+	}
+}
+
+// watchStreamInterrupt calls cancel on the first Ctrl-C byte read from stdin
+// while an assistant response is streaming, so Ctrl-C cancels just that
+// request instead of the whole process. This can't be done with
+// os/signal: raw mode turns off ISIG, so Ctrl-C never raises SIGINT, it only
+// ever arrives as a literal 0x03 byte, and nothing else is reading stdin
+// while a request is in flight. The returned stop function must be called
+// once the request finishes (success, failure, or cancellation) so the next
+// prompt's ReadLine gets stdin back; if the platform doesn't support read
+// deadlines on stdin, this silently gives up watching rather than erroring,
+// same as before per-request cancellation existed.
+func watchStreamInterrupt(cancel context.CancelFunc) (stop func()) {
+	const ctrlCByte = 3
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := os.Stdin.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+				return
+			}
+			n, err := os.Stdin.Read(buf)
+			if n > 0 && buf[0] == ctrlCByte {
+				cancel()
+				continue
+			}
+			if err != nil && !os.IsTimeout(err) {
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		_ = os.Stdin.SetReadDeadline(time.Time{})
+	}
+}
+
 func printChatHelp() {
-	fmt.Printf(`
+	printChatHelpTo(fmt.Printf)
+}
+
+// printChatHelpTo prints the chat help through print, so both the plain and
+// raw-mode front ends can share it (see printChatBanner).
+func printChatHelpTo(print func(format string, args ...any) (int, error)) {
+	print(`
 Available commands:
-  help  - Show this help message
-  clear - Clear conversation history and start a new session
-  exit  - Exit the chat session
-  quit  - Exit the chat session
+  help        - Show this help message
+  clear       - Clear conversation history and start a new session
+  /multiline  - Open a multi-line buffer, ended by a lone "." on its own line
+  /save PATH  - Save the conversation transcript as JSON to PATH
+  /load PATH  - Replace the conversation with a transcript saved by /save
+  exit, quit  - Exit the chat session
 
 Chat parameters:
   Temperature: %.1f
@@ -275,6 +528,23 @@ func init() {
 	chatCmd.Flags().Float64Var(&temperature, "temperature", 0.7, "Sampling temperature (0.0 to 2.0)")
 	chatCmd.Flags().IntVar(&maxTokens, "max-tokens", 1000, "Maximum number of tokens to generate")
 	chatCmd.Flags().BoolVar(&streaming, "stream", true, "Stream assistant responses")
+	chatCmd.Flags().IntVar(&streamTimeoutSecs, "stream-timeout", 30, "Seconds to wait for the next streamed event before treating the connection as stalled (0 disables)")
+
+	// Scriptable one-shot mode: set any of --prompt/--prompt-file/--input to
+	// send a single message and exit instead of starting the REPL.
+	chatCmd.Flags().StringVar(&chatPrompt, "prompt", "", "Send a single message non-interactively and exit")
+	chatCmd.Flags().StringVar(&chatPromptFile, "prompt-file", "", "Send the contents of a file as a single message non-interactively and exit")
+	chatCmd.Flags().StringVar(&chatInput, "input", "", "Use \"-\" to read a single message from stdin non-interactively")
+	chatCmd.Flags().StringVar(&chatSystem, "system", "", "Prepend a system message (scriptable mode)")
+	chatCmd.Flags().StringVar(&chatSystemFile, "system-file", "", "Prepend the contents of a file as a system message (scriptable mode)")
+	chatCmd.Flags().StringVar(&chatOutput, "output", "text", "Output encoding for scriptable mode: text, json, or jsonl")
+	chatCmd.Flags().BoolVar(&chatNoStream, "no-stream", false, "Disable streaming in scriptable mode")
+	chatCmd.Flags().BoolVar(&chatRaw, "raw", false, "Print the server's raw JSON response/SSE payloads in scriptable mode")
+
+	// Tool/function calling: --tools loads definitions, --tool-confirm gates
+	// execution behind an interactive y/n per call.
+	chatCmd.Flags().StringVar(&chatToolsFile, "tools", "", "YAML file defining tools the assistant may call (name, parameters schema, and exec: or http:)")
+	chatCmd.Flags().BoolVar(&chatToolConfirm, "tool-confirm", false, "Prompt for y/n confirmation before running each tool call")
 
 	// No flags are required now - they can come from config file
 
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+    "context"
     "io"
     "net/http"
     "net/http/httptest"
@@ -35,7 +36,7 @@ func TestSendChatRequestStream_SSE(t *testing.T) {
 
     // Prepare messages
     msgs := []ChatMessage{{Role: "user", Content: "hi"}}
-    got, err := sendChatRequestStream(msgs)
+    got, err := sendChatRequestStream(context.Background(), io.Discard, msgs)
     if err != nil {
         t.Fatalf("unexpected err: %v", err)
     }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"golang.org/x/term"
+
+	"llamafarm-cli/internal/runtime"
+)
+
+// pullProgressRenderer tracks per-layer download progress from a Docker
+// Engine API pull stream and renders it as a TTY progress bar, falling back
+// to periodic textual updates when stderr isn't a terminal. It's shared by
+// the server auto-start path and any future `lf pull`-style command.
+type pullProgressRenderer struct {
+	isTTY      bool
+	layers     map[string]*layerProgress
+	order      []string
+	lastRender time.Time
+}
+
+type layerProgress struct {
+	status  string
+	current int64
+	total   int64
+	done    bool
+}
+
+func newPullProgressRenderer() *pullProgressRenderer {
+	return &pullProgressRenderer{
+		isTTY:  term.IsTerminal(int(os.Stderr.Fd())),
+		layers: map[string]*layerProgress{},
+	}
+}
+
+// streamPullProgress consumes a Docker Engine API pull event stream and
+// renders progress to stderr until the channel closes or an error event
+// arrives.
+func streamPullProgress(client runtime.Client, image string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	events, err := client.Pull(ctx, image)
+	if err != nil {
+		return err
+	}
+
+	r := newPullProgressRenderer()
+	for ev := range events {
+		if ev.Error != "" {
+			fmt.Fprintln(os.Stderr)
+			return fmt.Errorf("%s", ev.Error)
+		}
+		r.update(ev)
+	}
+	r.finish()
+	return nil
+}
+
+func (r *pullProgressRenderer) update(ev runtime.PullEvent) {
+	if ev.ID == "" {
+		// Non-layer status lines (e.g. "Pulling from ...") are just printed once.
+		fmt.Fprintln(os.Stderr, ev.Status)
+		return
+	}
+
+	lp, ok := r.layers[ev.ID]
+	if !ok {
+		lp = &layerProgress{}
+		r.layers[ev.ID] = lp
+		r.order = append(r.order, ev.ID)
+	}
+	lp.status = ev.Status
+	lp.current = ev.ProgressDetail.Current
+	lp.total = ev.ProgressDetail.Total
+	if ev.Status == "Pull complete" || ev.Status == "Already exists" {
+		lp.done = true
+	}
+
+	if r.isTTY {
+		r.renderTTY()
+		return
+	}
+	// Non-TTY: only print every couple of seconds to avoid log spam.
+	if time.Since(r.lastRender) < 2*time.Second && !lp.done {
+		return
+	}
+	r.lastRender = time.Now()
+	fmt.Fprintf(os.Stderr, "%s: %s (%d/%d)\n", ev.ID, lp.status, lp.current, lp.total)
+}
+
+// renderTTY redraws all tracked layer bars in place, sorted by layer id for
+// stable ordering across redraws.
+func (r *pullProgressRenderer) renderTTY() {
+	ids := append([]string(nil), r.order...)
+	sort.Strings(ids)
+
+	// Move cursor up to overwrite the previous render, then print each bar.
+	if r.lastRender.IsZero() {
+		r.lastRender = time.Now()
+	} else {
+		fmt.Fprintf(os.Stderr, "\x1b[%dA", len(ids))
+	}
+	for _, id := range ids {
+		lp := r.layers[id]
+		fmt.Fprintf(os.Stderr, "\x1b[2K%s\n", renderLayerBar(id, lp))
+	}
+}
+
+func renderLayerBar(id string, lp *layerProgress) string {
+	if lp.done {
+		return fmt.Sprintf("%s: %s", id, lp.status)
+	}
+	if lp.total <= 0 {
+		return fmt.Sprintf("%s: %s", id, lp.status)
+	}
+	const width = 30
+	filled := int(float64(width) * float64(lp.current) / float64(lp.total))
+	if filled > width {
+		filled = width
+	}
+	bar := ""
+	for i := 0; i < width; i++ {
+		if i < filled {
+			bar += "="
+		} else {
+			bar += " "
+		}
+	}
+	pct := float64(lp.current) / float64(lp.total) * 100
+	return fmt.Sprintf("%s: [%s] %5.1f%% %s", id, bar, pct, lp.status)
+}
+
+func (r *pullProgressRenderer) finish() {
+	if len(r.order) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Pull complete.")
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"llamafarm-cli/internal/runtime"
+)
+
+func TestStreamPullProgress_Success(t *testing.T) {
+	fake := runtime.NewFake()
+	if err := streamPullProgress(fake, "llamafarm/server:latest"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.PullLog) != 1 || fake.PullLog[0] != "llamafarm/server:latest" {
+		t.Fatalf("expected Pull to be called with the image, got %v", fake.PullLog)
+	}
+}
+
+func TestStreamPullProgress_PullError(t *testing.T) {
+	fake := runtime.NewFake()
+	fake.PullErr = errors.New("engine unreachable")
+
+	err := streamPullProgress(fake, "llamafarm/server:latest")
+	if err == nil || err.Error() != "engine unreachable" {
+		t.Fatalf("expected the Pull error to surface, got %v", err)
+	}
+}
+
+func TestFakeClient_SatisfiesRuntimeClient(t *testing.T) {
+	var _ runtime.Client = runtime.NewFake()
+
+	fake := runtime.NewFake()
+	ctx := context.Background()
+
+	if _, err := fake.Run(ctx, runtime.RunOptions{Name: "llamafarm-server"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	running, err := fake.IsRunning(ctx, "llamafarm-server")
+	if err != nil || !running {
+		t.Fatalf("expected the container started by Run to report as running, got running=%v err=%v", running, err)
+	}
+
+	if err := fake.Stop(ctx, "llamafarm-server", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if running, _ := fake.IsRunning(ctx, "llamafarm-server"); running {
+		t.Fatalf("expected the container to report as stopped after Stop")
+	}
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,6 +10,7 @@ import (
 var debug bool
 var serverURL string
 var serverStartTimeout time.Duration
+var outputFormat string
 
 var rootCmd = &cobra.Command{
 	Use:   "lf",
@@ -18,6 +18,16 @@ var rootCmd = &cobra.Command{
 	Long: `LlamaFarm CLI is a command line interface for managing and interacting
 with your LlamaFarm projects. It provides various commands to help you
 manage your data, configurations, models,and operations.`,
+	// Commands that return a categorized *errs.CLIError via RunE (see
+	// cmd/errors.go) handle their own error reporting through HandleError
+	// below; without these, cobra would additionally print its own
+	// "Error: ..." and usage text for the same failure.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initLogger()
+		installSignalCleanup()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default behavior when no subcommand is specified
 		fmt.Println("Welcome to LlamaFarm!")
@@ -29,8 +39,7 @@ manage your data, configurations, models,and operations.`,
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		HandleError(err)
 	}
 }
 
@@ -39,4 +48,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug output")
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server-url", "", "LlamaFarm server URL (default: http://localhost:8000)")
 	rootCmd.PersistentFlags().DurationVar(&serverStartTimeout, "server-start-timeout", 45*time.Second, "How long to wait for local server to become ready when auto-starting (e.g. 45s, 1m)")
-}
\ No newline at end of file
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Structured log level: debug, info, warn, or error (default: info, or $LLAMAFARM_LOG_LEVEL)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Structured log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Error output format: text or json")
+}
@@ -7,10 +7,12 @@ import (
 
     "github.com/spf13/cobra"
     "llamafarm-cli/cmd/config"
+    "llamafarm-cli/internal/errs"
 )
 
 var (
-    runInputFile string
+    runInputFile   string
+    runPrintConfig bool
 )
 
 // runCmd represents the `lf run` command
@@ -69,7 +71,14 @@ Examples:
         }
         return nil
     },
-    Run: func(cmd *cobra.Command, args []string) {
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if runPrintConfig {
+            if err := printEffectiveConfig(""); err != nil {
+                return errs.New(errs.ErrConfigInvalid, err)
+            }
+            return nil
+        }
+
         // Resolve project and input according to args pattern
         var ns, proj string
 
@@ -78,8 +87,7 @@ Examples:
         if runInputFile != "" {
             data, err := os.ReadFile(runInputFile)
             if err != nil {
-                fmt.Fprintf(os.Stderr, "Error reading file '%s': %v\n", runInputFile, err)
-                os.Exit(1)
+                return errs.New(errs.ErrUser, fmt.Errorf("reading file '%s': %w", runInputFile, err))
             }
             input = string(data)
         } else if len(args) >= 1 {
@@ -104,8 +112,7 @@ Examples:
         // Resolve server configuration (strict): if ns/proj are absent, require from llamafarm.yaml
         serverCfg, err := config.GetServerConfig("", serverURL, ns, proj)
         if err != nil {
-            fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-            os.Exit(1)
+            return errs.New(errs.ErrUser, err)
         }
         serverURL = serverCfg.URL
         ns = serverCfg.Namespace
@@ -113,8 +120,7 @@ Examples:
 
         // Ensure server is up (auto-start locally if needed)
         if err := ensureServerAvailable(serverURL); err != nil {
-            fmt.Fprintf(os.Stderr, "Error ensuring server availability: %v\n", err)
-            os.Exit(1)
+            return errs.New(errs.ErrServerUnavailable, fmt.Errorf("ensuring server availability: %w", err))
         }
 
         // Construct context and call the project-scoped chat completions via shared helpers
@@ -130,8 +136,7 @@ Examples:
         messages := []ChatMessage{{Role: "user", Content: input}}
         resp, err := sendChatRequestWithContext(messages, ctx)
         if err != nil {
-            fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-            os.Exit(1)
+            return err
         }
         if len(resp.Choices) > 0 {
             if len(resp.Choices) == 1 {
@@ -145,10 +150,12 @@ Examples:
         } else {
             fmt.Println("No response received.")
         }
+        return nil
     },
 }
 
 func init() {
     runCmd.Flags().StringVarP(&runInputFile, "file", "f", "", "path to file containing input text")
+    runCmd.Flags().BoolVar(&runPrintConfig, "print-config", false, "print the fully merged effective llamafarm.yaml and exit")
     rootCmd.AddCommand(runCmd)
 }
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"llamafarm-cli/internal/runtime"
+
+	"github.com/spf13/cobra"
+)
+
+const managedServerContainerName = "llamafarm-server"
+
+// serverCmd groups lifecycle commands for the locally auto-started server
+// container, giving it parity with the designer's start/stop/logs triad.
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Manage the local LlamaFarm server container",
+	Long: `Manage the LlamaFarm server container that 'lf' auto-starts on localhost.
+
+Available commands:
+  stop   - Stop the running server container
+  logs   - Print recent logs from the server container
+  status - Report whether the server container is running`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("LlamaFarm Server Management")
+		cmd.Help()
+	},
+}
+
+var serverStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the local LlamaFarm server container",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := stopManagedContainer(ctx, managedServerContainerName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error stopping server: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Stopped.")
+	},
+}
+
+var serverStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report whether the local LlamaFarm server container is running",
+	Run: func(cmd *cobra.Command, args []string) {
+		if isContainerRunning(managedServerContainerName) {
+			fmt.Println("running")
+			return
+		}
+		fmt.Println("not running")
+	},
+}
+
+var serverLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print recent logs from the local LlamaFarm server container",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		client := runtime.NewClient("")
+		if err := client.Ping(ctx); err == nil {
+			rc, err := client.Logs(ctx, managedServerContainerName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching logs: %v\n", err)
+				os.Exit(1)
+			}
+			defer rc.Close()
+			runtime.CopyLogs(os.Stdout, os.Stderr, rc)
+			return
+		}
+
+		rt, err := detectContainerRuntime()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		out, err := containerLogsViaCLI(rt, managedServerContainerName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching logs: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(out)
+	},
+}
+
+func init() {
+	serverCmd.AddCommand(serverStopCmd)
+	serverCmd.AddCommand(serverStatusCmd)
+	serverCmd.AddCommand(serverLogsCmd)
+	rootCmd.AddCommand(serverCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"llamafarm-cli/internal/runtime"
+)
+
+// managedContainerLabels are attached to any container we start, so future
+// commands (and humans running `docker ps`) can identify what LlamaFarm
+// owns without guessing from the container name alone.
+var managedContainerLabels = map[string]string{
+	"com.llamafarm.managed": "true",
+	"com.llamafarm.version": Version,
+}
+
+// startLocalServerViaEngineAPI starts the server container using the Docker
+// Engine HTTP API over the local socket instead of shelling out to the CLI.
+// It returns an error (without side effects beyond the failed dial) when the
+// socket is unreachable, so callers can fall back to the CLI-based path.
+func startLocalServerViaEngineAPI(serverURL string) error {
+	client := runtime.NewClient("")
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		return err
+	}
+
+	containerName := "llamafarm-server"
+	image := "ghcr.io/llama-farm/llamafarm/server:latest"
+	port := resolvePort(serverURL, 8000)
+
+	running, err := client.IsRunning(ctx, containerName)
+	if err != nil {
+		return err
+	}
+	if running {
+		return nil
+	}
+
+	exists, err := client.ContainerExists(ctx, containerName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return client.Start(ctx, containerName)
+	}
+
+	fmt.Fprintln(os.Stderr, "Starting local LlamaFarm server via Docker Engine API...")
+	if err := streamPullProgress(client, image); err != nil {
+		// Best-effort, matching the CLI path: fall through and let `run`
+		// surface a clearer error if the image truly isn't available.
+		fmt.Fprintf(os.Stderr, "Warning: pull failed: %v\n", err)
+	}
+
+	opts := runtime.RunOptions{
+		Name:   containerName,
+		Image:  image,
+		Ports:  map[string]string{"8000/tcp": fmt.Sprintf("%d", port)},
+		Labels: managedContainerLabels,
+	}
+	if cwd, err := os.Getwd(); err == nil && strings.TrimSpace(cwd) != "" {
+		opts.Binds = append(opts.Binds, fmt.Sprintf("%s:%s", cwd, cwd))
+	}
+	if v, ok := os.LookupEnv("OLLAMA_HOST"); ok && strings.TrimSpace(v) != "" {
+		opts.Env = append(opts.Env, fmt.Sprintf("OLLAMA_HOST=%s", v))
+	} else if isHostOllamaAvailable() {
+		opts.Env = append(opts.Env, "OLLAMA_HOST=http://host.docker.internal:11434/v1")
+	}
+	if v, ok := os.LookupEnv("OLLAMA_PORT"); ok && strings.TrimSpace(v) != "" {
+		opts.Env = append(opts.Env, fmt.Sprintf("OLLAMA_PORT=%s", v))
+	}
+
+	runCtx, runCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer runCancel()
+	if _, err := client.Run(runCtx, opts); err != nil {
+		return fmt.Errorf("failed to start docker container: %w", err)
+	}
+	markServerAutoStarted(containerName)
+	return nil
+}
+
+// stopManagedContainer stops the named container, preferring the Docker
+// Engine API and falling back to the detected runtime's CLI.
+func stopManagedContainer(ctx context.Context, name string) error {
+	client := runtime.NewClient("")
+	if err := client.Ping(ctx); err == nil {
+		return client.Stop(ctx, name, 10*time.Second)
+	}
+
+	rt, err := detectContainerRuntime()
+	if err != nil {
+		return err
+	}
+	stopCmd := exec.CommandContext(ctx, rt.Binary(), "stop", name)
+	out, err := stopCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, string(out))
+	}
+	return nil
+}
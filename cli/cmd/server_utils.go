@@ -11,6 +11,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"llamafarm-cli/internal/errs"
 )
 
 // ensureServerAvailable verifies the server at serverURL is reachable.
@@ -18,21 +20,24 @@ import (
 // server via Docker, then waits for readiness. Returns an error if it
 // ultimately cannot ensure availability.
 func ensureServerAvailable(serverURL string) error {
+	start := time.Now()
 	if serverURL == "" {
 		serverURL = "http://localhost:8000"
 	}
+	logger.Debug("ensuring server availability", "server_url", serverURL)
 
 	if err := checkServerHealth(serverURL); err == nil {
+		logger.Info("server available", "server_url", serverURL, "latency_ms", time.Since(start).Milliseconds())
 		return nil
 	}
 
 	// Only attempt auto-start when pointing to localhost
 	if !isLocalhost(serverURL) {
-		return fmt.Errorf("server %s is not reachable", serverURL)
+		return errs.New(errs.ErrServerUnavailable, fmt.Errorf("server %s is not reachable", serverURL))
 	}
 
 	if err := startLocalServerViaDocker(serverURL); err != nil {
-		return err
+		return errs.New(errs.ErrDockerMissing, err)
 	}
 
 	// Poll for readiness
@@ -43,6 +48,7 @@ func ensureServerAvailable(serverURL string) error {
 	deadline := time.Now().Add(timeout)
 	for {
 		if err := checkServerHealth(serverURL); err == nil {
+			logger.Info("server available after auto-start", "server_url", serverURL, "latency_ms", time.Since(start).Milliseconds())
 			return nil
 		}
 		if time.Now().After(deadline) {
@@ -50,7 +56,7 @@ func ensureServerAvailable(serverURL string) error {
 		}
 		time.Sleep(1 * time.Second)
 	}
-	return fmt.Errorf("server did not become ready at %s within timeout", serverURL)
+	return errs.New(errs.ErrServerUnavailable, fmt.Errorf("server did not become ready at %s within timeout", serverURL))
 }
 
 // checkServerHealth pings the /info endpoint with a short timeout.
@@ -89,26 +95,43 @@ func isLocalhost(serverURL string) bool {
 
 // startLocalServerViaDocker pulls and runs the LlamaFarm server container if needed.
 // It uses a fixed container name and maps the serverURL port to container port 8000.
+// Despite the name, it auto-detects Docker, Podman, or nerdctl via
+// detectContainerRuntime so auto-start also works on Fedora/RHEL and other
+// Docker-less rootless setups. EnsureReady starts the Podman machine VM on
+// macOS first if needed, and the image reference is normalized per runtime
+// (e.g. qualified with "docker.io/" for Podman) before any pull/run.
+//
+// It prefers talking to the Docker Engine API directly over the local
+// socket (structured errors, streamed pull progress); if that path returns
+// any error (the socket isn't reachable, or an API call fails), it falls
+// back to shelling out to the detected runtime's CLI.
 func startLocalServerViaDocker(serverURL string) error {
-	// Ensure Docker is available
-	if err := ensureDockerAvailable(); err != nil {
+	if err := startLocalServerViaEngineAPI(serverURL); err == nil {
+		return nil
+	}
+
+	rt, err := detectContainerRuntime()
+	if err != nil {
+		return err
+	}
+	if err := rt.EnsureReady(); err != nil {
 		return err
 	}
 
 	port := resolvePort(serverURL, 8000)
 	containerName := "llamafarm-server"
-	image := "ghcr.io/llama-farm/llamafarm/server:latest"
+	image := rt.NormalizeImage("ghcr.io/llama-farm/llamafarm/server:latest")
 
 	// If a container with this name exists and is running, nothing to do
-	if isContainerRunning(containerName) {
+	if isContainerRunningRT(rt, containerName) {
 		return nil
 	}
 
-	fmt.Fprintln(os.Stderr, "Starting local LlamaFarm server via Docker...")
+	logger.Info("starting local server", "runtime", rt.Name(), "server_url", serverURL)
 
 	// Try to start existing stopped container first
-	if containerExists(containerName) {
-		startCmd := exec.Command("docker", "start", containerName)
+	if containerExistsRT(rt, containerName) {
+		startCmd := exec.Command(rt.Binary(), "start", containerName)
 		startCmd.Stdout = os.Stdout
 		startCmd.Stderr = os.Stderr
 		if err := startCmd.Run(); err == nil {
@@ -117,7 +140,10 @@ func startLocalServerViaDocker(serverURL string) error {
 	}
 
 	// Pull latest image (best effort)
-	_ = pullImage(image)
+	pullCmd := exec.Command(rt.Binary(), "pull", image)
+	pullCmd.Stdout = os.Stdout
+	pullCmd.Stderr = os.Stderr
+	_ = pullCmd.Run()
 
 	// Run new container
 	runArgs := []string{
@@ -126,6 +152,7 @@ func startLocalServerViaDocker(serverURL string) error {
 		"--name", containerName,
 		"-p", fmt.Sprintf("%d:8000", port),
 	}
+	runArgs = append(runArgs, rt.ExtraRunArgs()...)
 
 	// Mount current working directory into the container at the same path
 	if cwd, err := os.Getwd(); err == nil && strings.TrimSpace(cwd) != "" {
@@ -135,12 +162,14 @@ func startLocalServerViaDocker(serverURL string) error {
 	}
 
 	// Pass through or configure Ollama access inside the container
+	hostGateway := rt.HostGatewayHost()
 	if v, ok := os.LookupEnv("OLLAMA_HOST"); ok && strings.TrimSpace(v) != "" {
 		runArgs = append(runArgs, "-e", fmt.Sprintf("OLLAMA_HOST=%s", v))
 	} else if isHostOllamaAvailable() {
-		// Ensure the container can resolve host.docker.internal on Linux
-		runArgs = append(runArgs, "--add-host", "host.docker.internal:host-gateway")
-		runArgs = append(runArgs, "-e", "OLLAMA_HOST=http://host.docker.internal:11434/v1")
+		// Ensure the container can resolve the host gateway hostname; Podman
+		// exposes host.containers.internal instead of host.docker.internal.
+		runArgs = append(runArgs, "--add-host", fmt.Sprintf("%s:host-gateway", hostGateway))
+		runArgs = append(runArgs, "-e", fmt.Sprintf("OLLAMA_HOST=http://%s:11434/v1", hostGateway))
 	}
 	// Also pass through OLLAMA_HOST/OLLAMA_PORT if explicitly set by the user
 	if v, ok := os.LookupEnv("OLLAMA_HOST"); ok && strings.TrimSpace(v) != "" {
@@ -152,11 +181,12 @@ func startLocalServerViaDocker(serverURL string) error {
 
 	// Image last
 	runArgs = append(runArgs, image)
-	runCmd := exec.Command("docker", runArgs...)
+	runCmd := exec.Command(rt.Binary(), runArgs...)
 	runOut, err := runCmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to start docker container: %v\n%s", err, string(runOut))
+		return fmt.Errorf("failed to start %s container: %v\n%s", rt.Name(), err, string(runOut))
 	}
+	markServerAutoStarted(containerName)
 	return nil
 }
 
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// managedServerContainer is the name of the container this invocation
+// started (if any). ensureServerAvailable sets it after a successful
+// auto-start so the signal handler knows whether it's responsible for
+// cleaning it up; a server the user already had running is left alone.
+var managedServerContainer atomic.Value // string
+
+func markServerAutoStarted(containerName string) {
+	managedServerContainer.Store(containerName)
+}
+
+// shutdownCallbacks are cleanup hooks registered by long-running commands
+// (the chat REPL, dataset uploads) that need to react to the first
+// shutdown signal beyond stopping an auto-started server. installSignalCleanup
+// runs every registered callback, in registration order, before exiting —
+// the single place any command-specific shutdown logic runs, so it can't
+// race the global handler the way independent signal.Notify/NotifyContext
+// calls used to.
+var (
+	shutdownMu        sync.Mutex
+	shutdownCallbacks []func()
+)
+
+// onShutdown registers fn to run once, synchronously, from
+// installSignalCleanup's goroutine when the process receives its first
+// shutdown signal. It returns an unregister function for callers whose
+// cleanup need is scoped to part of a command (e.g. only while an upload is
+// in flight), so a signal arriving after that scope ends doesn't act on
+// stale state.
+func onShutdown(fn func()) (unregister func()) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	idx := len(shutdownCallbacks)
+	shutdownCallbacks = append(shutdownCallbacks, fn)
+	return func() {
+		shutdownMu.Lock()
+		defer shutdownMu.Unlock()
+		shutdownCallbacks[idx] = nil
+	}
+}
+
+func runShutdownCallbacks() {
+	shutdownMu.Lock()
+	callbacks := append([]func(){}, shutdownCallbacks...)
+	shutdownMu.Unlock()
+	for _, fn := range callbacks {
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// installSignalCleanup traps SIGINT/SIGTERM (and SIGQUIT when DEBUG is set)
+// so a Ctrl+C during a long-running command runs every registered shutdown
+// callback (see onShutdown) — aborting an in-flight dataset upload, ending a
+// chat session cleanly, etc. — and stops any container this invocation
+// auto-started, instead of leaving an orphan behind with the user's cwd
+// bind-mounted. Routing all of this through one handler, rather than each
+// concern installing its own independent signal.Notify/NotifyContext, is
+// what makes the cleanup order deterministic instead of racing. The counter
+// makes repeated signals idempotent: the first runs cleanup, the second
+// skips it and exits immediately, and the third force-kills the process.
+func installSignalCleanup() {
+	sigs := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if os.Getenv("DEBUG") != "" {
+		sigs = append(sigs, syscall.SIGQUIT)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+
+	var count int32
+	go func() {
+		for range sigCh {
+			n := atomic.AddInt32(&count, 1)
+			switch n {
+			case 1:
+				fmt.Fprintln(os.Stderr, "\nShutting down...")
+				runShutdownCallbacks()
+				cleanupManagedServer()
+				os.Exit(130)
+			case 2:
+				fmt.Fprintln(os.Stderr, "Skipping cleanup, exiting immediately.")
+				os.Exit(130)
+			default:
+				fmt.Fprintln(os.Stderr, "Force quitting.")
+				os.Exit(137)
+			}
+		}
+	}()
+}
+
+func cleanupManagedServer() {
+	name, _ := managedServerContainer.Load().(string)
+	if name == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := stopManagedContainer(ctx, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to stop %s: %v\n", name, err)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"llamafarm-cli/internal/starter"
+
+	"github.com/spf13/cobra"
+)
+
+// starterCmd groups commands that manage the local cache of project
+// starter bundles used by `lf init --template`.
+var starterCmd = &cobra.Command{
+	Use:   "starter",
+	Short: "Manage cached project starter bundles",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var starterAddCmd = &cobra.Command{
+	Use:   "add <ref>",
+	Short: "Fetch a starter bundle into the local cache",
+	Long:  `Fetches a Git or http(s) tarball starter reference into the local starter cache, so it's available offline for future 'lf init --template'.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := starter.Add(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Cached %s in %s\n", args[0], dir)
+	},
+}
+
+var starterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List builtin and cached starter bundles",
+	Run: func(cmd *cobra.Command, args []string) {
+		builtins, err := starter.BuiltinNames()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Builtin:")
+		for _, name := range builtins {
+			fmt.Printf("  %s\n", name)
+		}
+
+		cached, err := starter.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Cached:")
+		if len(cached) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, name := range cached {
+			fmt.Printf("  %s\n", name)
+		}
+	},
+}
+
+var starterRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a cached starter bundle",
+	Long:  `Removes a starter bundle previously cached by 'lf starter add', by the name shown in 'lf starter list'. Builtin starters can't be removed.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := starter.Remove(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s\n", args[0])
+	},
+}
+
+func init() {
+	starterCmd.AddCommand(starterAddCmd)
+	starterCmd.AddCommand(starterListCmd)
+	starterCmd.AddCommand(starterRemoveCmd)
+	rootCmd.AddCommand(starterCmd)
+}
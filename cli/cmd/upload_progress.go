@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// uploadProgressRenderer tracks per-file byte progress across one or more
+// concurrent uploads and renders it as TTY bars, falling back to periodic
+// textual updates when stderr isn't a terminal. It mirrors the shape of
+// pullProgressRenderer but is safe to update from multiple goroutines, since
+// datasets ingest --parallel drives several uploads at once.
+type uploadProgressRenderer struct {
+	mu         sync.Mutex
+	isTTY      bool
+	enabled    bool
+	files      map[string]*fileProgress
+	order      []string
+	lastRender time.Time
+}
+
+type fileProgress struct {
+	current int64
+	total   int64
+	done    bool
+}
+
+// newUploadProgressRenderer returns a renderer. enabled gates whether
+// anything is ever printed, so --no-progress and --silent can both just
+// pass false here instead of threading checks through every call site.
+func newUploadProgressRenderer(enabled bool) *uploadProgressRenderer {
+	return &uploadProgressRenderer{
+		isTTY:   enabled && term.IsTerminal(int(os.Stderr.Fd())),
+		enabled: enabled,
+		files:   map[string]*fileProgress{},
+	}
+}
+
+// barFor registers name (if new) and returns a handle uploaders use to
+// report bytes written as the upload proceeds.
+func (r *uploadProgressRenderer) barFor(name string, total int64) *uploadBar {
+	r.mu.Lock()
+	if _, ok := r.files[name]; !ok {
+		r.files[name] = &fileProgress{total: total}
+		r.order = append(r.order, name)
+	}
+	r.mu.Unlock()
+	return &uploadBar{renderer: r, name: name}
+}
+
+func (r *uploadProgressRenderer) setTotal(name string, total int64) {
+	r.mu.Lock()
+	if fp, ok := r.files[name]; ok {
+		fp.total = total
+	}
+	r.mu.Unlock()
+}
+
+func (r *uploadProgressRenderer) advance(name string, n int64) {
+	if n == 0 {
+		return
+	}
+	r.mu.Lock()
+	fp := r.files[name]
+	fp.current += n
+	if fp.total > 0 && fp.current >= fp.total {
+		fp.done = true
+	}
+	r.mu.Unlock()
+	r.render()
+}
+
+func (r *uploadProgressRenderer) render() {
+	if !r.enabled {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isTTY {
+		if r.lastRender.IsZero() {
+			r.lastRender = time.Now()
+		} else {
+			fmt.Fprintf(os.Stderr, "\x1b[%dA", len(r.order))
+		}
+		for _, name := range r.order {
+			fmt.Fprintf(os.Stderr, "\x1b[2K%s\n", renderFileBar(name, r.files[name]))
+		}
+		return
+	}
+
+	// Non-TTY: only print every couple of seconds to avoid log spam.
+	if time.Since(r.lastRender) < 2*time.Second && !r.allDoneLocked() {
+		return
+	}
+	r.lastRender = time.Now()
+	for _, name := range r.order {
+		fmt.Fprintf(os.Stderr, "%s\n", renderFileBar(name, r.files[name]))
+	}
+}
+
+func (r *uploadProgressRenderer) allDoneLocked() bool {
+	for _, fp := range r.files {
+		if !fp.done {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *uploadProgressRenderer) finish() {
+	if !r.enabled || len(r.order) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Upload complete.")
+}
+
+func renderFileBar(name string, fp *fileProgress) string {
+	if fp.total <= 0 {
+		return fmt.Sprintf("%s: %d bytes", name, fp.current)
+	}
+	const width = 30
+	filled := int(float64(width) * float64(fp.current) / float64(fp.total))
+	if filled > width {
+		filled = width
+	}
+	bar := ""
+	for i := 0; i < width; i++ {
+		if i < filled {
+			bar += "="
+		} else {
+			bar += " "
+		}
+	}
+	pct := float64(fp.current) / float64(fp.total) * 100
+	return fmt.Sprintf("%s: [%s] %5.1f%%", name, bar, pct)
+}
+
+// uploadBar is a handle to one file's slot in a shared uploadProgressRenderer.
+// A nil *uploadBar is valid and simply discards progress, so callers that
+// don't care about progress can pass nil instead of threading a renderer.
+type uploadBar struct {
+	renderer *uploadProgressRenderer
+	name     string
+}
+
+func (b *uploadBar) setTotal(total int64) {
+	if b == nil {
+		return
+	}
+	b.renderer.setTotal(b.name, total)
+}
+
+func (b *uploadBar) advance(n int64) {
+	if b == nil {
+		return
+	}
+	b.renderer.advance(b.name, n)
+}
+
+// teeWriter returns an io.Writer that advances this bar by the number of
+// bytes written to it, for use with io.TeeReader over a chunk body.
+func (b *uploadBar) teeWriter() io.Writer {
+	return progressWriter(func(p []byte) (int, error) {
+		b.advance(int64(len(p)))
+		return len(p), nil
+	})
+}
+
+type progressWriter func(p []byte) (int, error)
+
+func (f progressWriter) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,49 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// LoadBaseline reads a Summary previously written by SaveBaseline, for
+// --fail-on-decrease comparisons.
+func LoadBaseline(path string) (*Summary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// SaveBaseline writes s as JSON to path, for a later --fail-on-decrease run
+// to compare against.
+func SaveBaseline(path string, s *Summary) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ChangedFiles returns the repo-relative paths of files that differ between
+// baseRef and the working tree, for --diff restriction.
+func ChangedFiles(baseRef string) ([]string, error) {
+	out, err := exec.Command("git", "diff", "--name-only", baseRef).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --name-only %s: %w", baseRef, err)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,275 @@
+// Package coverage parses Go coverprofiles and produces coverage reports in
+// the formats lf dev coverage exposes (text/markdown/json summaries, LCOV,
+// and Cobertura XML). It's shared by the cobra command and by the
+// standalone tools/cover2lcov and tools/coverreport binaries, which are now
+// thin wrappers around it.
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileStats holds line/statement/function coverage totals for one source
+// file. Lines holds the per-line hit count (max across merged profiles) and
+// is omitted from JSON output; it's kept around for formats that need it
+// (LCOV, Cobertura).
+type FileStats struct {
+	File        string      `json:"file"`
+	LineCovered int         `json:"line_covered"`
+	LineTotal   int         `json:"line_total"`
+	StmtCovered int         `json:"stmt_covered"`
+	StmtTotal   int         `json:"stmt_total"`
+	FuncCovered int         `json:"func_covered"`
+	FuncTotal   int         `json:"func_total"`
+	Lines       map[int]int `json:"-"`
+}
+
+// LinePct, FuncPct, and StmtPct return the file's coverage as a percentage
+// (0-100), or 0 if the respective total is 0.
+func (s FileStats) LinePct() float64 { return pct(s.LineCovered, s.LineTotal) }
+func (s FileStats) FuncPct() float64 { return pct(s.FuncCovered, s.FuncTotal) }
+func (s FileStats) StmtPct() float64 { return pct(s.StmtCovered, s.StmtTotal) }
+
+func pct(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(covered) * 100.0) / float64(total)
+}
+
+// Summary is a full coverage report: per-file stats plus totals across all
+// files, as produced by Merge.
+type Summary struct {
+	Files []FileStats `json:"files"`
+	Total FileStats   `json:"total"`
+}
+
+// segment is one parsed coverprofile line: a line range within a file and
+// its statement count/hit count.
+type segment struct {
+	file               string
+	startLine, endLine int
+	numStmt, hits      int
+}
+
+// parseProfile reads one Go coverprofile (the format `go test -coverprofile`
+// writes) into its raw segments, skipping the leading "mode:" line.
+func parseProfile(path string) ([]segment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var segments []segment
+	scanner := bufio.NewScanner(f)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			first = false
+			if strings.HasPrefix(line, "mode:") {
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Format: filename:startLine.startCol,endLine.endCol numStatements count
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		fileAndRange := parts[0]
+		frIdx := strings.LastIndex(fileAndRange, ":")
+		if frIdx < 0 {
+			continue
+		}
+		file := fileAndRange[:frIdx]
+		rngParts := strings.Split(fileAndRange[frIdx+1:], ",")
+		if len(rngParts) != 2 {
+			continue
+		}
+		start := strings.Split(rngParts[0], ".")
+		end := strings.Split(rngParts[1], ".")
+		if len(start) < 1 || len(end) < 1 {
+			continue
+		}
+		startLine, err1 := strconv.Atoi(start[0])
+		endLine, err2 := strconv.Atoi(end[0])
+		nStmt, err3 := strconv.Atoi(parts[1])
+		hits, err4 := strconv.Atoi(parts[2])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		segments = append(segments, segment{file: file, startLine: startLine, endLine: endLine, numStmt: nStmt, hits: hits})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return segments, nil
+}
+
+// Merge reads and combines one or more Go coverprofiles into a single
+// Summary, deduplicating overlapping line ranges by taking the max hit
+// count per line (the same rule a single profile already applies to its own
+// overlapping segments, e.g. from table-driven subtests exercising the same
+// line).
+func Merge(paths []string) (*Summary, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no coverprofiles given")
+	}
+
+	lineHits := map[string]map[int]int{}
+	stmtTotal := map[string]int{}
+	stmtCovered := map[string]int{}
+
+	for _, path := range paths {
+		segments, err := parseProfile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, seg := range segments {
+			if _, ok := lineHits[seg.file]; !ok {
+				lineHits[seg.file] = map[int]int{}
+			}
+			for ln := seg.startLine; ln <= seg.endLine; ln++ {
+				if cur, ok := lineHits[seg.file][ln]; !ok || seg.hits > cur {
+					lineHits[seg.file][ln] = seg.hits
+				}
+			}
+			stmtTotal[seg.file] += seg.numStmt
+			if seg.hits > 0 {
+				stmtCovered[seg.file] += seg.numStmt
+			}
+		}
+	}
+
+	// Function coverage has no native breakdown in the coverprofile format,
+	// so it's derived from `go tool cover -func`, same as the original
+	// coverreport tool. Only the first profile is used for this best-effort
+	// breakdown when merging multiple profiles.
+	funcTotal, funcCovered := funcCoverage(paths[0])
+
+	files := make([]string, 0, len(lineHits))
+	for f := range lineHits {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	summary := &Summary{}
+	for _, fpath := range files {
+		lines := lineHits[fpath]
+		var ltot, lcov int
+		for _, hits := range lines {
+			ltot++
+			if hits > 0 {
+				lcov++
+			}
+		}
+		st := FileStats{
+			File:        fpath,
+			LineCovered: lcov,
+			LineTotal:   ltot,
+			StmtCovered: stmtCovered[fpath],
+			StmtTotal:   stmtTotal[fpath],
+			FuncCovered: funcCovered[fpath],
+			FuncTotal:   funcTotal[fpath],
+			Lines:       lines,
+		}
+		summary.Files = append(summary.Files, st)
+		summary.Total.LineCovered += st.LineCovered
+		summary.Total.LineTotal += st.LineTotal
+		summary.Total.StmtCovered += st.StmtCovered
+		summary.Total.StmtTotal += st.StmtTotal
+		summary.Total.FuncCovered += st.FuncCovered
+		summary.Total.FuncTotal += st.FuncTotal
+	}
+	return summary, nil
+}
+
+// funcCoverage shells out to `go tool cover -func` for per-function
+// coverage. Returns empty maps (best effort) if the go tool isn't
+// available or the profile can't be analyzed.
+func funcCoverage(path string) (total, covered map[string]int) {
+	total = map[string]int{}
+	covered = map[string]int{}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return total, covered
+	}
+	out, err := exec.Command(goBin, "tool", "cover", "-func="+path).Output()
+	if err != nil {
+		return total, covered
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		t := strings.TrimSpace(scanner.Text())
+		if t == "" || strings.HasPrefix(t, "total:") {
+			continue
+		}
+		// format: path/file.go:line:\tFuncName\tXX.X%
+		parts := strings.SplitN(t, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		lhs, pctStr := parts[0], parts[1]
+		fp := lhs
+		if i := strings.Index(lhs, ":"); i >= 0 {
+			fp = lhs[:i]
+		}
+		total[fp]++
+		p := strings.TrimSpace(strings.TrimSuffix(pctStr, "%"))
+		if val, err := strconv.ParseFloat(p, 64); err == nil && val > 0 {
+			covered[fp]++
+		}
+	}
+	return total, covered
+}
+
+// FilterFiles returns a copy of s restricted to files whose path ends with
+// one of keep (coverprofile paths are fully-qualified module import paths,
+// while e.g. `git diff --name-only` reports repo-relative paths, so this
+// matches by suffix rather than equality). An empty keep returns s
+// unchanged.
+func (s *Summary) FilterFiles(keep []string) *Summary {
+	if len(keep) == 0 {
+		return s
+	}
+	filtered := &Summary{}
+	for _, st := range s.Files {
+		for _, k := range keep {
+			if k != "" && strings.HasSuffix(st.File, k) {
+				filtered.Files = append(filtered.Files, st)
+				filtered.Total.LineCovered += st.LineCovered
+				filtered.Total.LineTotal += st.LineTotal
+				filtered.Total.StmtCovered += st.StmtCovered
+				filtered.Total.StmtTotal += st.StmtTotal
+				filtered.Total.FuncCovered += st.FuncCovered
+				filtered.Total.FuncTotal += st.FuncTotal
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// MeetsThreshold reports whether every one of line, statement, and function
+// total coverage is at least thresholdPct.
+func (s *Summary) MeetsThreshold(thresholdPct float64) bool {
+	return s.Total.LinePct() >= thresholdPct && s.Total.StmtPct() >= thresholdPct && s.Total.FuncPct() >= thresholdPct
+}
+
+// DecreasedFrom reports whether s's total line, statement, or function
+// coverage is lower than baseline's.
+func (s *Summary) DecreasedFrom(baseline *Summary) bool {
+	return s.Total.LinePct() < baseline.Total.LinePct() ||
+		s.Total.StmtPct() < baseline.Total.StmtPct() ||
+		s.Total.FuncPct() < baseline.Total.FuncPct()
+}
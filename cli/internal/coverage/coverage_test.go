@@ -0,0 +1,102 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleProfile = `mode: set
+llamafarm-cli/cmd/example.go:3.2,5.3 2 1
+llamafarm-cli/cmd/example.go:7.2,9.3 1 0
+llamafarm-cli/cmd/other.go:3.2,5.3 1 1
+`
+
+func writeProfile(t *testing.T, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "coverage.out")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing temp profile: %v", err)
+	}
+	return path
+}
+
+func TestMerge(t *testing.T) {
+	path := writeProfile(t, sampleProfile)
+
+	summary, err := Merge([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(summary.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(summary.Files))
+	}
+	if summary.Total.LineTotal != 9 {
+		t.Fatalf("expected 9 total lines, got %d", summary.Total.LineTotal)
+	}
+	if summary.Total.LineCovered != 6 {
+		t.Fatalf("expected 6 covered lines, got %d", summary.Total.LineCovered)
+	}
+}
+
+func TestMerge_NoPaths(t *testing.T) {
+	if _, err := Merge(nil); err == nil {
+		t.Fatal("expected error for empty paths")
+	}
+}
+
+func TestFilterFiles(t *testing.T) {
+	path := writeProfile(t, sampleProfile)
+	summary, err := Merge([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	filtered := summary.FilterFiles([]string{"cmd/example.go"})
+	if len(filtered.Files) != 1 {
+		t.Fatalf("expected 1 file after filtering, got %d", len(filtered.Files))
+	}
+	if filtered.Files[0].File != "llamafarm-cli/cmd/example.go" {
+		t.Fatalf("unexpected file kept: %s", filtered.Files[0].File)
+	}
+
+	unfiltered := summary.FilterFiles(nil)
+	if len(unfiltered.Files) != len(summary.Files) {
+		t.Fatalf("expected empty keep to return summary unchanged")
+	}
+}
+
+func TestMeetsThreshold(t *testing.T) {
+	// Constructed directly rather than via Merge, since Merge's function
+	// coverage shells out to `go tool cover -func` against real files on
+	// disk, which the fixture profile above doesn't have.
+	summary := &Summary{Total: FileStats{
+		LineCovered: 6, LineTotal: 9,
+		StmtCovered: 6, StmtTotal: 9,
+		FuncCovered: 6, FuncTotal: 9,
+	}}
+
+	if summary.MeetsThreshold(90) {
+		t.Fatal("expected threshold of 90%% not to be met")
+	}
+	if !summary.MeetsThreshold(50) {
+		t.Fatal("expected threshold of 50%% to be met")
+	}
+}
+
+func TestDecreasedFrom(t *testing.T) {
+	path := writeProfile(t, sampleProfile)
+	summary, err := Merge([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	better := &Summary{Total: FileStats{LineCovered: 100, LineTotal: 100, StmtCovered: 100, StmtTotal: 100, FuncCovered: 100, FuncTotal: 100}}
+	if !summary.DecreasedFrom(better) {
+		t.Fatal("expected decrease against a 100%% baseline")
+	}
+	if summary.DecreasedFrom(&Summary{}) {
+		t.Fatal("expected no decrease against an empty baseline")
+	}
+}
@@ -0,0 +1,160 @@
+package coverage
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// trimPath strips prefix from file if present, else converts it to
+// slash-separated form for display.
+func trimPath(file, prefix string) string {
+	if prefix != "" {
+		if idx := strings.Index(file, prefix); idx >= 0 {
+			return file[idx+len(prefix):]
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// WriteSummaryText writes s as a fixed-width text table.
+func WriteSummaryText(w io.Writer, s *Summary, trimPrefix string) {
+	fmt.Fprintf(w, "%-60s %18s %20s %22s\n", "File", "Lines (cov/total)", "Functions (cov/total)", "Statements (cov/total)")
+	for _, st := range s.Files {
+		fmt.Fprintf(w, "%-60s %7d/%-7d (%5.1f%%) %7d/%-7d (%5.1f%%) %7d/%-7d (%5.1f%%)\n",
+			trimPath(st.File, trimPrefix),
+			st.LineCovered, st.LineTotal, st.LinePct(),
+			st.FuncCovered, st.FuncTotal, st.FuncPct(),
+			st.StmtCovered, st.StmtTotal, st.StmtPct())
+	}
+	fmt.Fprintf(w, "%-60s %7d/%-7d (%5.1f%%) %7d/%-7d (%5.1f%%) %7d/%-7d (%5.1f%%)\n",
+		"total",
+		s.Total.LineCovered, s.Total.LineTotal, s.Total.LinePct(),
+		s.Total.FuncCovered, s.Total.FuncTotal, s.Total.FuncPct(),
+		s.Total.StmtCovered, s.Total.StmtTotal, s.Total.StmtPct())
+}
+
+// WriteSummaryMarkdown writes s as a markdown table with the same columns
+// as WriteSummaryText.
+func WriteSummaryMarkdown(w io.Writer, s *Summary, trimPrefix string) {
+	fmt.Fprintln(w, "| File | Lines (cov/total) | Functions (cov/total) | Statements (cov/total) |")
+	fmt.Fprintln(w, "|---|---:|---:|---:|")
+	for _, st := range s.Files {
+		fmt.Fprintf(w, "| %s | %d/%d (%.1f%%) | %d/%d (%.1f%%) | %d/%d (%.1f%%) |\n",
+			trimPath(st.File, trimPrefix),
+			st.LineCovered, st.LineTotal, st.LinePct(),
+			st.FuncCovered, st.FuncTotal, st.FuncPct(),
+			st.StmtCovered, st.StmtTotal, st.StmtPct())
+	}
+	fmt.Fprintf(w, "| total | %d/%d (%.1f%%) | %d/%d (%.1f%%) | %d/%d (%.1f%%) |\n",
+		s.Total.LineCovered, s.Total.LineTotal, s.Total.LinePct(),
+		s.Total.FuncCovered, s.Total.FuncTotal, s.Total.FuncPct(),
+		s.Total.StmtCovered, s.Total.StmtTotal, s.Total.StmtPct())
+}
+
+// WriteSummaryJSON writes s as indented JSON.
+func WriteSummaryJSON(w io.Writer, s *Summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteLCOV writes s in LCOV tracefile format (one TN/SF/DA.../end_of_record
+// block per file), normalizing hit counts to 0/1 same as the original
+// cover2lcov tool.
+func WriteLCOV(w io.Writer, s *Summary) {
+	for _, st := range s.Files {
+		fmt.Fprintf(w, "TN:\n")
+		fmt.Fprintf(w, "SF:%s\n", st.File)
+		lines := make([]int, 0, len(st.Lines))
+		for ln := range st.Lines {
+			lines = append(lines, ln)
+		}
+		sort.Ints(lines)
+		for _, ln := range lines {
+			hit := 0
+			if st.Lines[ln] > 0 {
+				hit = 1
+			}
+			fmt.Fprintf(w, "DA:%d,%d\n", ln, hit)
+		}
+		fmt.Fprintf(w, "end_of_record\n")
+	}
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+type coberturaClass struct {
+	Name     string          `xml:"name,attr"`
+	Filename string          `xml:"filename,attr"`
+	LineRate float64         `xml:"line-rate,attr"`
+	Methods  struct{}        `xml:"methods"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaPackage struct {
+	Name     string           `xml:"name,attr"`
+	LineRate float64          `xml:"line-rate,attr"`
+	Classes  []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaReport struct {
+	XMLName      xml.Name           `xml:"coverage"`
+	LineRate     float64            `xml:"line-rate,attr"`
+	LinesCovered int                `xml:"lines-covered,attr"`
+	LinesValid   int                `xml:"lines-valid,attr"`
+	Packages     []coberturaPackage `xml:"packages>package"`
+}
+
+// WriteCobertura writes s as Cobertura XML, the format GitLab/Jenkins
+// coverage widgets understand. Each file becomes its own <package>/<class>
+// pair; LlamaFarm's coverprofiles are per-file, not per-package, so there's
+// no meaningful grouping above that.
+func WriteCobertura(w io.Writer, s *Summary) error {
+	report := coberturaReport{
+		LineRate:     s.Total.LinePct() / 100,
+		LinesCovered: s.Total.LineCovered,
+		LinesValid:   s.Total.LineTotal,
+	}
+	for _, st := range s.Files {
+		lines := make([]int, 0, len(st.Lines))
+		for ln := range st.Lines {
+			lines = append(lines, ln)
+		}
+		sort.Ints(lines)
+		class := coberturaClass{
+			Name:     filepath.Base(st.File),
+			Filename: filepath.ToSlash(st.File),
+			LineRate: st.LinePct() / 100,
+		}
+		for _, ln := range lines {
+			class.Lines = append(class.Lines, coberturaLine{Number: ln, Hits: st.Lines[ln]})
+		}
+		report.Packages = append(report.Packages, coberturaPackage{
+			Name:     st.File,
+			LineRate: st.LinePct() / 100,
+			Classes:  []coberturaClass{class},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`+"\n"); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,144 @@
+// Package datasetcache implements a content-addressed local object store
+// for dataset files, shared by `lf datasets ingest`/`add` (to dedup
+// re-uploads of unchanged files) and `lf datasets verify` (to check the
+// local corpus for on-disk corruption). Objects live under
+// ~/.llamafarm/cache/objects/<sha256[:2]>/<sha256>, mirroring how git and
+// Docker lay out their own content-addressed stores.
+package datasetcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Status describes the result of verifying a cached object against its
+// digest.
+type Status int
+
+const (
+	// StatusOK means the object is present and still hashes to its own name.
+	StatusOK Status = iota
+	// StatusMissing means no object exists for the digest yet.
+	StatusMissing
+	// StatusCorrupt means an object exists but no longer hashes to its own
+	// name, e.g. due to bit rot or an out-of-band edit.
+	StatusCorrupt
+)
+
+// Dir returns ~/.llamafarm/cache, the root of the object store.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".llamafarm", "cache"), nil
+}
+
+func objectPath(cacheDir, sha string) string {
+	return filepath.Join(cacheDir, "objects", sha[:2], sha)
+}
+
+// HashFile streams path through sha256 without loading it whole into
+// memory, returning its digest and size.
+func HashFile(path string) (sha string, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err = io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// Put hashes src and stores it in the content-addressed object store,
+// deduping on digest: if an object for this content already exists, it's
+// left untouched. Otherwise src is hardlinked into the store when it's on
+// the same filesystem, falling back to a copy. Returns the digest, the
+// object's path in the store, and the file's size.
+func Put(src string) (sha, path string, size int64, err error) {
+	sha, size, err = HashFile(src)
+	if err != nil {
+		return "", "", 0, err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", "", 0, err
+	}
+	dest := objectPath(dir, sha)
+	if _, statErr := os.Stat(dest); statErr == nil {
+		return sha, dest, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", "", 0, err
+	}
+	if err := os.Link(src, dest); err != nil {
+		if copyErr := copyFile(src, dest); copyErr != nil {
+			return "", "", 0, fmt.Errorf("caching %s: %w", src, copyErr)
+		}
+	}
+	return sha, dest, size, nil
+}
+
+// copyFile copies src to dest via a temp file plus rename, so a failed copy
+// never leaves a partial object behind.
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dest + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// ETag formats sha as a quoted HTTP entity tag suitable for an
+// If-None-Match precondition header, e.g. `"sha256:abcd..."`.
+func ETag(sha string) string {
+	return fmt.Sprintf("%q", "sha256:"+sha)
+}
+
+// Verify checks that the cached object for sha is present and still hashes
+// to its own name, catching on-disk corruption of the object store.
+func Verify(sha string) (Status, error) {
+	dir, err := Dir()
+	if err != nil {
+		return StatusMissing, err
+	}
+	path := objectPath(dir, sha)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return StatusMissing, nil
+		}
+		return StatusMissing, err
+	}
+	got, _, err := HashFile(path)
+	if err != nil {
+		return StatusCorrupt, err
+	}
+	if got != sha {
+		return StatusCorrupt, fmt.Errorf("expected sha256:%s, got sha256:%s", sha, got)
+	}
+	return StatusOK, nil
+}
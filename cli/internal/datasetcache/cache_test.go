@@ -0,0 +1,83 @@
+package datasetcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPutDedupsAndVerifies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(path, []byte("hello world"), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	sha, objPath, size, err := Put(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != int64(len("hello world")) {
+		t.Fatalf("expected size 11, got %d", size)
+	}
+	if _, err := os.Stat(objPath); err != nil {
+		t.Fatalf("expected object to exist at %s: %v", objPath, err)
+	}
+
+	status, err := Verify(sha)
+	if err != nil {
+		t.Fatalf("unexpected error verifying: %v", err)
+	}
+	if status != StatusOK {
+		t.Fatalf("expected StatusOK, got %v", status)
+	}
+
+	// Putting again should be a no-op dedup, not an error.
+	sha2, objPath2, _, err := Put(path)
+	if err != nil {
+		t.Fatalf("unexpected error on re-put: %v", err)
+	}
+	if sha2 != sha || objPath2 != objPath {
+		t.Fatalf("expected re-put to return the same digest and path")
+	}
+}
+
+func TestVerifyMissing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	status, err := Verify("0000000000000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != StatusMissing {
+		t.Fatalf("expected StatusMissing, got %v", status)
+	}
+}
+
+func TestVerifyCorrupt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(path, []byte("original"), 0o644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+	sha, objPath, _, err := Put(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.WriteFile(objPath, []byte("tampered"), 0o644); err != nil {
+		t.Fatalf("tampering object: %v", err)
+	}
+
+	status, err := Verify(sha)
+	if err == nil {
+		t.Fatalf("expected error for corrupt object")
+	}
+	if status != StatusCorrupt {
+		t.Fatalf("expected StatusCorrupt, got %v", status)
+	}
+}
@@ -0,0 +1,111 @@
+// Package errs provides a small set of categorized, stack-carrying errors
+// shared across cmd/, so a handful of call sites (server/Docker
+// auto-start, config loading, the chat HTTP client) can report failures in
+// one consistent shape instead of each formatting its own message and
+// calling os.Exit directly. cmd.HandleError is the single place that turns
+// a CLIError into process output and an exit code.
+package errs
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Code categorizes a CLIError for exit-code mapping (see ExitCode) and for
+// --output=json consumers that want to react to a failure programmatically
+// rather than scrape its message.
+type Code string
+
+const (
+	// ErrUser covers invalid flags/arguments and other user-caused input
+	// errors that don't fit a more specific category below.
+	ErrUser Code = "user_error"
+	// ErrServerUnavailable means the LlamaFarm server couldn't be reached
+	// or failed to come up within the auto-start timeout.
+	ErrServerUnavailable Code = "server_unavailable"
+	// ErrServer means the server was reached but returned an error
+	// response.
+	ErrServer Code = "server_error"
+	// ErrDockerMissing means the server couldn't be auto-started through a
+	// container runtime: none (Docker/Podman/nerdctl) was usable, or starting
+	// the server container with it failed.
+	ErrDockerMissing Code = "docker_missing"
+	// ErrConfigInvalid means llamafarm.yaml (or one of its conf.d/LF_ENV
+	// layers) failed to load or parse.
+	ErrConfigInvalid Code = "config_invalid"
+	// ErrAuth means the server rejected the request's credentials.
+	ErrAuth Code = "auth_error"
+	// ErrProjectNotFound means the referenced namespace/project doesn't
+	// exist server-side.
+	ErrProjectNotFound Code = "project_not_found"
+	// ErrInternal is the fallback for anything uncategorized.
+	ErrInternal Code = "internal_error"
+)
+
+// CLIError is a categorized error carrying a stack trace captured at the
+// point it was first wrapped, so --debug can show where a failure actually
+// originated rather than just its message.
+type CLIError struct {
+	Code   Code
+	Err    error
+	Stack  string
+	Detail map[string]any
+}
+
+func (e *CLIError) Error() string { return e.Err.Error() }
+
+func (e *CLIError) Unwrap() error { return e.Err }
+
+// New wraps err under code, capturing a stack trace. Mirrors
+// emperror.dev/errors' WithStackIf idiom: if err is already a *CLIError,
+// its existing stack (and category) is kept rather than overwritten, since
+// that's where the failure actually originated.
+func New(code Code, err error) *CLIError {
+	if err == nil {
+		return nil
+	}
+	if existing, ok := err.(*CLIError); ok {
+		return existing
+	}
+	return &CLIError{Code: code, Err: err, Stack: string(debug.Stack())}
+}
+
+// Newf is New with a formatted message wrapping err, e.g.
+// errs.Newf(errs.ErrConfigInvalid, err, "loading %s", path).
+func Newf(code Code, err error, format string, args ...any) *CLIError {
+	return New(code, fmt.Errorf(format+": %w", append(append([]any{}, args...), err)...))
+}
+
+// WithDetail attaches a structured field surfaced under --output=json
+// (e.g. the HTTP status of a failed server request), returning e for
+// chaining.
+func (e *CLIError) WithDetail(key string, value any) *CLIError {
+	if e.Detail == nil {
+		e.Detail = map[string]any{}
+	}
+	e.Detail[key] = value
+	return e
+}
+
+// ExitCode maps a CLIError's category to the process exit code Execute
+// should use: 2 = user error, 3 = server, 4 = docker, 5 = config. Anything
+// else (auth, project-not-found, internal, or a plain uncategorized error)
+// exits 1, matching cobra's own default.
+func ExitCode(err error) int {
+	ce, ok := err.(*CLIError)
+	if !ok {
+		return 1
+	}
+	switch ce.Code {
+	case ErrUser:
+		return 2
+	case ErrServerUnavailable, ErrServer:
+		return 3
+	case ErrDockerMissing:
+		return 4
+	case ErrConfigInvalid:
+		return 5
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,67 @@
+package errs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNew_CapturesStackOnce(t *testing.T) {
+	base := errors.New("boom")
+	wrapped := New(ErrServer, base)
+	if wrapped.Stack == "" {
+		t.Fatalf("expected a captured stack trace")
+	}
+	if wrapped.Unwrap() != base {
+		t.Fatalf("expected Unwrap to return the original error")
+	}
+
+	rewrapped := New(ErrDockerMissing, wrapped)
+	if rewrapped != wrapped {
+		t.Fatalf("expected re-wrapping an existing CLIError to return it unchanged")
+	}
+	if rewrapped.Code != ErrServer {
+		t.Fatalf("expected the original category to survive re-wrapping, got %s", rewrapped.Code)
+	}
+}
+
+func TestNewf_FormatsMessage(t *testing.T) {
+	base := errors.New("not found")
+	wrapped := Newf(ErrConfigInvalid, base, "loading %s", "llamafarm.yaml")
+	if wrapped.Error() != "loading llamafarm.yaml: not found" {
+		t.Fatalf("unexpected message: %s", wrapped.Error())
+	}
+	if !errors.Is(wrapped, base) {
+		t.Fatalf("expected errors.Is to see through to the wrapped error")
+	}
+}
+
+func TestWithDetail(t *testing.T) {
+	err := New(ErrServer, errors.New("boom")).WithDetail("status", 503)
+	if err.Detail["status"] != 503 {
+		t.Fatalf("unexpected detail: %+v", err.Detail)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		code Code
+		want int
+	}{
+		{ErrUser, 2},
+		{ErrServerUnavailable, 3},
+		{ErrServer, 3},
+		{ErrDockerMissing, 4},
+		{ErrConfigInvalid, 5},
+		{ErrAuth, 1},
+		{ErrInternal, 1},
+	}
+	for _, c := range cases {
+		got := ExitCode(New(c.code, errors.New("x")))
+		if got != c.want {
+			t.Fatalf("ExitCode(%s) = %d, want %d", c.code, got, c.want)
+		}
+	}
+	if got := ExitCode(errors.New("plain")); got != 1 {
+		t.Fatalf("expected a plain error to exit 1, got %d", got)
+	}
+}
@@ -0,0 +1,357 @@
+// Package runtime provides a typed client for the Docker Engine HTTP API,
+// used in place of shelling out to the `docker` CLI. Talking to the API
+// directly over the local socket gives structured errors, streamed pull
+// progress, and label-based discovery of LlamaFarm-managed containers,
+// none of which can be reliably parsed from CLI stdout.
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"time"
+)
+
+// DefaultSocket is the platform-default Docker Engine API endpoint.
+func DefaultSocket() string {
+	if runtime.GOOS == "windows" {
+		return `\\.\pipe\docker_engine`
+	}
+	return "/var/run/docker.sock"
+}
+
+// PullEvent mirrors one line of the streaming JSON response from
+// POST /images/create.
+type PullEvent struct {
+	Status         string `json:"status"`
+	ID             string `json:"id,omitempty"`
+	Progress       string `json:"progress,omitempty"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// RunOptions describes a container to create and start, capturing the
+// subset of the Engine API's container-create payload the CLI needs.
+type RunOptions struct {
+	Name    string
+	Image   string
+	Ports   map[string]string // containerPort -> hostPort, e.g. "8000/tcp" -> "8000"
+	Binds   []string          // "hostPath:containerPath"
+	Env     []string          // "KEY=value"
+	Labels  map[string]string
+	Network string
+}
+
+// Client is the abstraction the CLI commands depend on, so tests can
+// substitute an in-memory Fake instead of talking to a real Engine API.
+type Client interface {
+	Ping(ctx context.Context) error
+	Pull(ctx context.Context, image string) (<-chan PullEvent, error)
+	ContainerExists(ctx context.Context, name string) (bool, error)
+	IsRunning(ctx context.Context, name string) (bool, error)
+	Start(ctx context.Context, name string) error
+	Stop(ctx context.Context, name string, timeout time.Duration) error
+	Run(ctx context.Context, opts RunOptions) (containerID string, err error)
+	Logs(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// sockClient implements Client against the Docker Engine HTTP API over a
+// UNIX socket. Connections are always dialed as a UNIX socket, so the
+// named-pipe path DefaultSocket returns on Windows is not reached and Ping
+// fails there.
+type sockClient struct {
+	http *http.Client
+}
+
+// NewClient builds a Client bound to the given socket path. An empty path
+// uses DefaultSocket(). Ping should be used to confirm the socket is
+// actually reachable before relying on this client.
+func NewClient(socketPath string) Client {
+	if socketPath == "" {
+		socketPath = DefaultSocket()
+	}
+	return &sockClient{
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+			Timeout: 0, // streaming endpoints (pull, logs) must not be time-boxed here
+		},
+	}
+}
+
+// apiBase is a fixed host; it's never actually resolved since DialContext
+// always dials the UNIX socket, but the Engine API still expects a URL.
+const apiBase = "http://docker"
+
+func (c *sockClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBase+"/_ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("docker engine socket unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker engine ping returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *sockClient) Pull(ctx context.Context, image string) (<-chan PullEvent, error) {
+	url := fmt.Sprintf("%s/images/create?fromImage=%s", apiBase, encodeImageRef(image))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("pull %s failed (%d): %s", image, resp.StatusCode, string(body))
+	}
+
+	events := make(chan PullEvent)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var ev PullEvent
+			if err := dec.Decode(&ev); err != nil {
+				if err != io.EOF {
+					events <- PullEvent{Status: "error", Error: err.Error()}
+				}
+				return
+			}
+			events <- ev
+		}
+	}()
+	return events, nil
+}
+
+func (c *sockClient) ContainerExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.inspect(ctx, name)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *sockClient) IsRunning(ctx context.Context, name string) (bool, error) {
+	info, err := c.inspect(ctx, name)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return info.State.Running, nil
+}
+
+type inspectResult struct {
+	State struct {
+		Running bool `json:"Running"`
+	} `json:"State"`
+}
+
+type notFoundError struct{ msg string }
+
+func (e *notFoundError) Error() string { return e.msg }
+func isNotFound(err error) bool        { _, ok := err.(*notFoundError); return ok }
+
+func (c *sockClient) inspect(ctx context.Context, name string) (*inspectResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBase+"/containers/"+name+"/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &notFoundError{msg: "container " + name + " not found"}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("inspect %s failed (%d): %s", name, resp.StatusCode, string(body))
+	}
+	var out inspectResult
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *sockClient) Start(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+"/containers/"+name+"/start", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified {
+		return fmt.Errorf("start %s failed (%d)", name, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *sockClient) Stop(ctx context.Context, name string, timeout time.Duration) error {
+	url := fmt.Sprintf("%s/containers/%s/stop?t=%d", apiBase, name, int(timeout.Seconds()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified {
+		return fmt.Errorf("stop %s failed (%d)", name, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *sockClient) Run(ctx context.Context, opts RunOptions) (string, error) {
+	portBindings := map[string][]map[string]string{}
+	exposedPorts := map[string]struct{}{}
+	for containerPort, hostPort := range opts.Ports {
+		portBindings[containerPort] = []map[string]string{{"HostPort": hostPort}}
+		exposedPorts[containerPort] = struct{}{}
+	}
+
+	payload := map[string]any{
+		"Image":        opts.Image,
+		"Env":          opts.Env,
+		"Labels":       opts.Labels,
+		"ExposedPorts": exposedPorts,
+		"HostConfig": map[string]any{
+			"Binds":        opts.Binds,
+			"PortBindings": portBindings,
+			"NetworkMode":  opts.Network,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := apiBase + "/containers/create"
+	if opts.Name != "" {
+		url += "?name=" + opts.Name
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, newJSONReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("create container failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(respBody, &created); err != nil {
+		return "", err
+	}
+	if err := c.Start(ctx, created.ID); err != nil {
+		return created.ID, err
+	}
+	return created.ID, nil
+}
+
+func newJSONReader(body []byte) io.Reader { return bytes.NewReader(body) }
+
+func encodeImageRef(image string) string { return url.QueryEscape(image) }
+
+func (c *sockClient) Logs(ctx context.Context, name string) (io.ReadCloser, error) {
+	url := fmt.Sprintf("%s/containers/%s/logs?stdout=true&stderr=true&tail=200", apiBase, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("logs %s failed (%d): %s", name, resp.StatusCode, string(body))
+	}
+	return resp.Body, nil
+}
+
+// logStreamHeaderSize is the length of the frame header the Docker Engine
+// API prepends to each chunk of a Logs/attach response when the container
+// was created without a TTY: a 1-byte stream type (stdout/stderr), 3 bytes
+// of padding, then a big-endian uint32 payload length.
+const logStreamHeaderSize = 8
+
+const (
+	logStreamStdout = 1
+	logStreamStderr = 2
+)
+
+// CopyLogs demuxes a Logs stream and writes stdout frames to dst and
+// stderr frames to errDst, per the Docker Engine API's multiplexed log
+// format (see logStreamHeaderSize). None of our containers are started
+// with a TTY, so every Logs response needs this instead of a raw io.Copy,
+// which would interleave the binary frame headers into the visible output.
+func CopyLogs(dst, errDst io.Writer, src io.Reader) error {
+	header := make([]byte, logStreamHeaderSize)
+	for {
+		if _, err := io.ReadFull(src, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		size := binary.BigEndian.Uint32(header[4:8])
+		w := dst
+		if header[0] == logStreamStderr {
+			w = errDst
+		}
+		if _, err := io.CopyN(w, src, int64(size)); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
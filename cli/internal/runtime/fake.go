@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// Fake is an in-memory Client used in tests so callers don't need a real
+// Docker Engine socket. Running/Existing containers are tracked by name.
+type Fake struct {
+	Running  map[string]bool
+	PingErr  error
+	PullErr  error
+	RunErr   error
+	PullLog  []string
+	RunCalls []RunOptions
+}
+
+// NewFake returns a ready-to-use Fake client with no containers running.
+func NewFake() *Fake {
+	return &Fake{Running: map[string]bool{}}
+}
+
+func (f *Fake) Ping(ctx context.Context) error { return f.PingErr }
+
+func (f *Fake) Pull(ctx context.Context, image string) (<-chan PullEvent, error) {
+	if f.PullErr != nil {
+		return nil, f.PullErr
+	}
+	f.PullLog = append(f.PullLog, image)
+	events := make(chan PullEvent, 2)
+	events <- PullEvent{Status: "Downloading", ID: "layer1"}
+	events <- PullEvent{Status: "Pull complete", ID: "layer1"}
+	close(events)
+	return events, nil
+}
+
+func (f *Fake) ContainerExists(ctx context.Context, name string) (bool, error) {
+	_, ok := f.Running[name]
+	return ok, nil
+}
+
+func (f *Fake) IsRunning(ctx context.Context, name string) (bool, error) {
+	return f.Running[name], nil
+}
+
+func (f *Fake) Start(ctx context.Context, name string) error {
+	f.Running[name] = true
+	return nil
+}
+
+func (f *Fake) Stop(ctx context.Context, name string, timeout time.Duration) error {
+	f.Running[name] = false
+	return nil
+}
+
+func (f *Fake) Run(ctx context.Context, opts RunOptions) (string, error) {
+	if f.RunErr != nil {
+		return "", f.RunErr
+	}
+	f.RunCalls = append(f.RunCalls, opts)
+	f.Running[opts.Name] = true
+	return "fake-" + opts.Name, nil
+}
+
+func (f *Fake) Logs(ctx context.Context, name string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
@@ -0,0 +1,149 @@
+package starter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// cacheName turns a Git/HTTP reference into a filesystem-safe directory
+// name under the starter cache.
+func cacheName(ref string) string {
+	replacer := strings.NewReplacer("://", "_", "/", "_", ":", "_", "@", "_")
+	return replacer.Replace(ref)
+}
+
+// Add fetches ref — a Git remote (recognized by a "git::" prefix or a
+// ".git" suffix) or an http(s) tarball URL — into the starter cache,
+// returning its local directory. If ref is already cached, the existing
+// directory is reused rather than re-fetched; remove it first (see Remove)
+// to force a refresh.
+func Add(ref string) (string, error) {
+	root, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(root, cacheName(ref))
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	switch {
+	case strings.HasPrefix(ref, "git::") || strings.HasSuffix(ref, ".git"):
+		return dest, fetchGit(strings.TrimPrefix(ref, "git::"), dest)
+	case strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://"):
+		return dest, fetchHTTPTarball(ref, dest)
+	default:
+		return "", fmt.Errorf("unrecognized starter reference %q: expected a builtin name, a local directory, a Git URL (ending in .git), or an http(s) tarball URL", ref)
+	}
+}
+
+func fetchGit(url, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "clone", "--depth", "1", url, dest)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone %s: %w", url, err)
+	}
+	return nil
+}
+
+func fetchHTTPTarball(url, dest string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("downloading starter %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading starter %s: server returned %d", url, resp.StatusCode)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("starter %s is not a gzip tarball: %w", url, err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("extracting starter %s: %w", url, err)
+		}
+		target := filepath.Join(dest, filepath.Clean(string(filepath.Separator)+hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return fmt.Errorf("extracting starter %s: %w", url, err)
+			}
+			out.Close()
+		}
+	}
+}
+
+// List returns the names of every starter bundle currently cached on disk
+// (not including the builtins compiled into the binary).
+func List() ([]string, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Remove deletes a cached starter bundle by its cache directory name (as
+// reported by List), leaving the builtins untouched.
+func Remove(name string) error {
+	root, err := Dir()
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, name)
+	if _, err := os.Stat(target); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no cached starter named %q", name)
+		}
+		return err
+	}
+	return os.RemoveAll(target)
+}
@@ -0,0 +1,133 @@
+// Package starter resolves and renders project starter bundles for `lf
+// init --template`: a built-in name baked into the binary, a local
+// directory, or a remote Git/HTTP bundle fetched into a local cache under
+// $XDG_DATA_HOME/llamafarm/starters (the same layout Helm uses for its own
+// starter directory). `lf starter add|list|remove` manage that cache
+// directly.
+package starter
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+//go:embed all:builtins
+var builtinFS embed.FS
+
+const builtinsRoot = "builtins"
+
+// TemplateVars are the variables a starter bundle's files are rendered
+// with via text/template, e.g. `{{.ProjectName}}` in llamafarm.yaml.
+type TemplateVars struct {
+	ProjectName string
+	Namespace   string
+	Now         string
+}
+
+// Dir returns the local starter cache root, $XDG_DATA_HOME/llamafarm/starters,
+// falling back to ~/.local/share/llamafarm/starters when XDG_DATA_HOME isn't
+// set.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "llamafarm", "starters"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "llamafarm", "starters"), nil
+}
+
+// BuiltinNames lists the starter bundles compiled into the CLI itself, for
+// `lf starter list` to show alongside whatever's cached locally.
+func BuiltinNames() ([]string, error) {
+	entries, err := fs.ReadDir(builtinFS, builtinsRoot)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Resolve turns a --template reference into the filesystem it should be
+// rendered from and the root within it: a built-in name, an existing local
+// directory, or a remote Git/HTTP reference that's fetched into the starter
+// cache (see Add) the first time it's used.
+func Resolve(ref string) (fs.FS, string, error) {
+	if ref == "" {
+		return nil, "", fmt.Errorf("empty starter reference")
+	}
+
+	names, err := BuiltinNames()
+	if err != nil {
+		return nil, "", fmt.Errorf("listing builtin starters: %w", err)
+	}
+	for _, name := range names {
+		if name == ref {
+			return builtinFS, filepath.Join(builtinsRoot, name), nil
+		}
+	}
+
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return os.DirFS(ref), ".", nil
+	}
+
+	dir, err := Add(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	return os.DirFS(dir), ".", nil
+}
+
+// Render walks every file under root in srcFS, executes it as a
+// text/template with vars, and writes the result into the matching path
+// under destDir, creating directories as needed.
+func Render(srcFS fs.FS, root, destDir string, vars TemplateVars) error {
+	return fs.WalkDir(srcFS, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		target := filepath.Join(destDir, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		data, err := fs.ReadFile(srcFS, path)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(d.Name()).Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("parsing starter template %s: %w", rel, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		if err := tmpl.Execute(out, vars); err != nil {
+			return fmt.Errorf("rendering starter template %s: %w", rel, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,205 @@
+package starter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinNames(t *testing.T) {
+	names, err := BuiltinNames()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]bool{"docs-qa": true, "code-search": true}
+	if len(names) != len(want) {
+		t.Fatalf("expected %d builtins, got %d: %v", len(want), len(names), names)
+	}
+	for _, n := range names {
+		if !want[n] {
+			t.Fatalf("unexpected builtin name: %s", n)
+		}
+	}
+}
+
+func TestResolve_Builtin(t *testing.T) {
+	srcFS, root, err := Resolve("docs-qa")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := fs.ReadFile(srcFS, filepath.Join(root, "llamafarm.yaml"))
+	if err != nil {
+		t.Fatalf("reading bundle file: %v", err)
+	}
+	if !strings.Contains(string(data), "{{.ProjectName}}") {
+		t.Fatalf("expected the builtin's llamafarm.yaml to be an unrendered template, got: %s", data)
+	}
+}
+
+func TestResolve_LocalDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "llamafarm.yaml"), []byte("name: {{.ProjectName}}\n"), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	srcFS, root, err := Resolve(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root != "." {
+		t.Fatalf("expected root \".\" for a local directory, got %q", root)
+	}
+	if _, err := fs.ReadFile(srcFS, "llamafarm.yaml"); err != nil {
+		t.Fatalf("reading bundle file: %v", err)
+	}
+}
+
+func TestResolve_UnrecognizedReference(t *testing.T) {
+	if _, _, err := Resolve("not-a-real-starter"); err == nil {
+		t.Fatalf("expected an error for an unrecognized starter reference")
+	}
+}
+
+func TestRender_SubstitutesVarsAndPreservesStructure(t *testing.T) {
+	srcFS, root, err := Resolve("docs-qa")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dest := t.TempDir()
+	vars := TemplateVars{ProjectName: "shop", Namespace: "acme", Now: "2026-07-29T00:00:00Z"}
+	if err := Render(srcFS, root, dest, vars); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := os.ReadFile(filepath.Join(dest, "llamafarm.yaml"))
+	if err != nil {
+		t.Fatalf("expected llamafarm.yaml to be rendered: %v", err)
+	}
+	if !strings.Contains(string(cfg), "name: acme/shop") {
+		t.Fatalf("expected rendered vars in llamafarm.yaml, got: %s", cfg)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "prompts", "README.md")); err != nil {
+		t.Fatalf("expected the prompts/ subdirectory to be preserved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".env.example")); err != nil {
+		t.Fatalf("expected .env.example to be rendered: %v", err)
+	}
+}
+
+func TestCacheName_IsFilesystemSafe(t *testing.T) {
+	name := cacheName("https://example.com/starters/foo.git")
+	if filepath.Base(name) != name {
+		t.Fatalf("expected cacheName to strip path separators, got: %s", name)
+	}
+}
+
+func TestAdd_RejectsUnrecognizedReference(t *testing.T) {
+	if _, err := Add("not-a-url-or-git-ref"); err == nil {
+		t.Fatalf("expected an error for an unrecognized starter reference")
+	}
+}
+
+func TestListAndRemove_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+
+	root, err := Dir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cached := filepath.Join(root, "example_com_foo")
+	if err := os.MkdirAll(cached, 0o755); err != nil {
+		t.Fatalf("setting up fixture: %v", err)
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 1 || names[0] != "example_com_foo" {
+		t.Fatalf("unexpected cache listing: %v", names)
+	}
+
+	if err := Remove("example_com_foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(cached); !os.IsNotExist(err) {
+		t.Fatalf("expected the cached starter to be removed")
+	}
+
+	if err := Remove("example_com_foo"); err == nil {
+		t.Fatalf("expected an error removing an already-absent starter")
+	}
+}
+
+func buildTestTarball(t *testing.T, entries map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatalf("writing %s entry: %v", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatalf("writing %s content: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("closing tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("closing gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchHTTPTarball_ExtractsWithinDest(t *testing.T) {
+	pkg := buildTestTarball(t, map[string][]byte{"llamafarm.yaml": []byte("name: {{.ProjectName}}\n")})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pkg)
+	}))
+	defer ts.Close()
+
+	dest := filepath.Join(t.TempDir(), "bundle")
+	if err := fetchHTTPTarball(ts.URL, dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "llamafarm.yaml")); err != nil || !strings.Contains(string(data), "{{.ProjectName}}") {
+		t.Fatalf("expected extracted llamafarm.yaml, got %q (err %v)", data, err)
+	}
+}
+
+func TestFetchHTTPTarball_RejectsPathTraversal(t *testing.T) {
+	pkg := buildTestTarball(t, map[string][]byte{
+		"safe.txt":                           []byte("ok"),
+		"../../../../tmp/starter-escape.txt": []byte("pwned"),
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pkg)
+	}))
+	defer ts.Close()
+
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "bundle")
+	if err := fetchHTTPTarball(ts.URL, dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.ReadFile(filepath.Join(dest, "safe.txt")); err != nil {
+		t.Fatalf("expected safe.txt to be extracted normally: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "starter-escape.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected path traversal entry to stay within dest, but it escaped to %s", parent)
+	}
+	if _, err := os.Stat("/tmp/starter-escape.txt"); !os.IsNotExist(err) {
+		t.Fatalf("expected path traversal entry not to escape to /tmp")
+	}
+}